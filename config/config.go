@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"gopkg.in/yaml.v3"
 	"os"
+	"time"
 )
 
 // AppConfig 应用配置
@@ -13,12 +14,82 @@ type AppConf struct {
 		Port      string `yaml:"port"`
 		Debug     bool   `yaml:"debug"`
 		JWTSecret string `yaml:"jwt_secret"`
+		// 访问令牌有效期，如 "15m"，默认15分钟
+		AccessTokenExpire string `yaml:"access_token_expire"`
+		// 刷新令牌有效期，如 "168h"，默认7天
+		RefreshTokenExpire string `yaml:"refresh_token_expire"`
+		// 敏感字段（如TOTP密钥）的加密密钥，未配置时由 jwt_secret 派生
+		EncryptionKey string `yaml:"encryption_key"`
+		// 前端地址，用于拼接邮件中的链接
+		FrontendURL string `yaml:"frontend_url"`
+		// 注册模式：open（开放注册，默认）/ invite（需要邀请码）/ approval（需管理员审核）/ closed（关闭注册）
+		RegistrationMode string `yaml:"registration_mode"`
 	} `yaml:"app"`
 	Upload struct {
 		MaxSize      int      `yaml:"max_size"`
 		AllowedTypes []string `yaml:"allowed_types"`
 		SavePath     string   `yaml:"save_path"`
 	} `yaml:"upload"`
+	Mail struct {
+		Driver   string `yaml:"driver"`    // smtp / file / stdout
+		From     string `yaml:"from"`      // 发件人地址
+		Host     string `yaml:"host"`      // SMTP服务器
+		Port     string `yaml:"port"`      // SMTP端口
+		Username string `yaml:"username"`  // SMTP用户名
+		Password string `yaml:"password"`  // SMTP密码
+		FilePath string `yaml:"file_path"` // driver 为 file 时邮件写入的文件
+	} `yaml:"mail"`
+	Security struct {
+		LoginAttemptStore  string `yaml:"login_attempt_store"`  // 登录失败计数存储：database / memory
+		MaxAccountFailures int    `yaml:"max_account_failures"` // 单个账号允许的连续失败次数，默认5
+		MaxIPFailures      int    `yaml:"max_ip_failures"`      // 单个IP允许的连续失败次数，默认20
+		FailureWindow      string `yaml:"failure_window"`       // 失败计数的统计窗口，默认 "15m"
+		LockoutDuration    string `yaml:"lockout_duration"`     // 首次锁定时长，之后按指数递增，默认 "1m"
+		MaxLockoutDuration string `yaml:"max_lockout_duration"` // 最长锁定时长，默认 "1h"
+	} `yaml:"security"`
+	OAuth struct {
+		Providers []OAuthProviderConf `yaml:"providers"`
+	} `yaml:"oauth"`
+	Revision struct {
+		KeepLast int `yaml:"keep_last"` // 每篇文章最多保留的修订数，0表示不限制
+		KeepDays int `yaml:"keep_days"` // 修订保留天数，0表示不限制；两项都配置时同时生效，最新的修订始终保留
+	} `yaml:"revision"`
+	Search struct {
+		Backend string `yaml:"backend"` // 搜索索引：mysql（FULLTEXT + ngram 分词，默认）/ memory（进程内倒排索引，适用于单实例和测试）
+	} `yaml:"search"`
+	Privacy struct {
+		ErasureArticlePolicy string `yaml:"erasure_article_policy"` // 注销账号时文章的处理方式：reassign（转移到匿名账号，默认）/ delete
+	} `yaml:"privacy"`
+	JWT struct {
+		SigningKeyID string       `yaml:"signing_key_id"` // 当前用于签名的密钥 kid，未配置密钥时使用 jwt_secret 进行 HS256 签名
+		AcceptHS256  bool         `yaml:"accept_hs256"`   // 切换到非对称密钥后是否仍接受 jwt_secret 签发的 HS256 令牌（迁移期间使用）
+		Keys         []JWTKeyConf `yaml:"keys"`           // 签名/验签密钥，轮换期间可同时配置多个
+	} `yaml:"jwt"`
+}
+
+// JWTKeyConf JWT非对称密钥配置
+// 配置私钥的密钥可用于签名；只配置公钥的密钥仅用于验签（如已退役但签发的令牌尚未过期的密钥）
+type JWTKeyConf struct {
+	Kid            string `yaml:"kid"`              // 密钥ID，写入令牌头部的 kid
+	Algorithm      string `yaml:"algorithm"`        // RS256 / ES256 / EdDSA 等，未配置时按密钥类型推断
+	PrivateKeyFile string `yaml:"private_key_file"` // PEM格式私钥文件
+	PublicKeyFile  string `yaml:"public_key_file"`  // PEM格式公钥文件，配置了私钥时可省略
+}
+
+// OAuthProviderConf OpenID Connect 身份提供方配置
+// 只配置 issuer 时通过 /.well-known/openid-configuration 自动发现端点，
+// 也可以显式配置各端点（便于对接本地测试用的模拟身份提供方）
+type OAuthProviderConf struct {
+	Name         string   `yaml:"name"`          // 提供方名称，用于路由，如 google
+	DisplayName  string   `yaml:"display_name"`  // 显示名称
+	Issuer       string   `yaml:"issuer"`        // 签发者地址
+	ClientID     string   `yaml:"client_id"`     // 客户端ID
+	ClientSecret string   `yaml:"client_secret"` // 客户端密钥
+	RedirectURL  string   `yaml:"redirect_url"`  // 回调地址，如 http://localhost:8080/api/auth/oauth/google/callback
+	Scopes       []string `yaml:"scopes"`        // 授权范围，默认 openid email profile
+	AuthURL      string   `yaml:"auth_url"`      // 授权端点（可选）
+	TokenURL     string   `yaml:"token_url"`     // 令牌端点（可选）
+	JWKSURL      string   `yaml:"jwks_url"`      // 公钥端点（可选）
 }
 
 // DBConfig 数据库配置
@@ -74,3 +145,15 @@ func loadConfig(filePath string, config interface{}) error {
 
 	return nil
 }
+
+// ParseDurationOrDefault 解析时间间隔配置，为空或格式错误时返回默认值
+func ParseDurationOrDefault(value string, defaultValue time.Duration) time.Duration {
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return defaultValue
+	}
+	return d
+}
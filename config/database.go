@@ -52,7 +52,39 @@ func InitDB() error {
 	}
 
 	// 自动迁移
-	err = DB.AutoMigrate(&model.Article{}, &model.User{}, &model.Category{}, &model.Tag{}, &model.ArticleTag{}, &model.Like{}, &model.Comment{})
+	err = DB.AutoMigrate(
+		&model.Article{},
+		&model.User{},
+		&model.Category{},
+		&model.Tag{},
+		&model.ArticleTag{},
+		&model.Like{},
+		&model.Comment{},
+		&model.RefreshToken{},
+		&model.RevokedToken{},
+		&model.Session{},
+		&model.UserToken{},
+		&model.LoginAttempt{},
+		&model.LoginFailure{},
+		&model.PersonalAccessToken{},
+		&model.UserIdentity{},
+		&model.OAuthState{},
+		&model.Upload{},
+		&model.ErasureJob{},
+		&model.InviteCode{},
+		&model.ImpersonationEvent{},
+		&model.Follow{},
+		&model.TagFollow{},
+		&model.AuditEvent{},
+		&model.SlugHistory{},
+		&model.ArticleRevision{},
+		&model.SchedulerLock{},
+		&model.ReviewComment{},
+		&model.ArticleSearchDocument{},
+		&model.Series{},
+		&model.SeriesArticle{},
+		&model.ArticleAuthor{},
+	)
 	if err != nil {
 		return err
 	}
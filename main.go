@@ -5,8 +5,10 @@ import (
 	"gin-blog-system/middleware"
 	_ "gin-blog-system/model"
 	"gin-blog-system/router"
+	"gin-blog-system/service"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"time"
 )
 
 func main() {
@@ -15,13 +17,64 @@ func main() {
 		panic(err)
 	}
 
+	// 加载JWT签名密钥
+	if err := service.InitJWTKeys(); err != nil {
+		panic(err)
+	}
+
+	// 检查TOTP密钥、分页游标等使用的加密密钥
+	if err := service.CheckEncryptionKey(); err != nil {
+		panic(err)
+	}
+
+	// 初始化邮件发送器
+	if err := service.InitMailer(); err != nil {
+		panic(err)
+	}
+
 	// 2. 初始化数据库
 	if err := config.InitDB(); err != nil {
 		panic(err)
 	}
 
+	// 初始化登录失败计数存储
+	if err := service.InitLoginAttemptStore(); err != nil {
+		panic(err)
+	}
+
+	// 为已有的文章、分类和标签生成 slug
+	if err := service.BackfillSlugs(); err != nil {
+		panic(err)
+	}
+
+	// 为升级前已有的文章补充主作者署名
+	if err := service.BackfillArticleAuthors(); err != nil {
+		panic(err)
+	}
+
+	// 为尚未渲染或渲染规则已更新的文章生成 HTML
+	if err := service.RerenderStaleArticles(); err != nil {
+		panic(err)
+	}
+
+	// 初始化搜索索引并补全缺少的搜索文档
+	if err := service.InitSearchIndex(); err != nil {
+		panic(err)
+	}
+
+	// 继续执行未完成的账号注销任务
+	go service.ResumeErasureJobs()
+
+	// 定期清理过期的令牌记录
+	go service.StartTokenCleanup(time.Hour)
+
+	// 定时发布和下线文章（多实例部署时通过数据库锁只由一个实例执行）
+	go service.StartArticleScheduler(time.Minute)
+
 	// 3. 初始化 Gin 引擎
 	r := gin.New() // 使用 New() 而不是 Default()，以便我们可以自定义中间件
+	// 为每个请求分配请求ID，用于关联日志和审计记录
+	r.Use(middleware.RequestID())
 	// 添加增强版日志中间件
 	r.Use(middleware.EnhancedLogger())
 	// 添加数据库监控中间件
@@ -32,8 +85,9 @@ func main() {
 	// 👇 添加 CORS 中间件（关键修复）
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:3001", "http://localhost:3002", "http://localhost:3004"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", middleware.RequestIDHeader},
+		ExposeHeaders:    []string{middleware.RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * 60 * 60, // 12小时
 	}))
@@ -45,6 +99,8 @@ func main() {
 	router.RegisterRoutes(r)
 	// 注册健康检查路由
 	router.RegisterHealthRoutes(r)
+	// 注册JWKS等公开路由
+	router.RegisterWellKnownRoutes(r)
 
 	// 5. 启动服务（端口从配置读取，默认8080）
 	port := config.AppConfig.App.Port
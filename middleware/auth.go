@@ -1,24 +1,19 @@
 package middleware
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"net/http"
 	"strings"
 	"time"
 
-	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/service"
 )
 
-// Claims JWT自定义声明
-type Claims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	jwt.RegisteredClaims
-}
-
 // AuthMiddleware 认证中间件
-func AuthMiddleware() gin.HandlerFunc {
+// 默认只接受JWT；传入 scopes 时同时接受拥有其中任一权限范围的个人访问令牌
+func AuthMiddleware(scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.GetHeader("Authorization")
 		if token == "" {
@@ -38,13 +33,16 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 解析JWT token并验证过期时间
 		tokenString := strings.TrimPrefix(token, "Bearer ")
-		claims := &Claims{}
-		// 使用标准验证选项，包括过期时间验证
-		tokenObj, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(config.AppConfig.App.JWTSecret), nil
-		}, jwt.WithValidMethods([]string{"HS256"})) // 指定有效的签名方法
+
+		// 个人访问令牌
+		if strings.HasPrefix(tokenString, model.PersonalAccessTokenPrefix) {
+			authenticateAccessToken(c, tokenString, scopes)
+			return
+		}
+
+		// 解析JWT token并验证签名和过期时间（按 kid 选择验签密钥）
+		claims, err := service.ParseAccessToken(tokenString)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": "Invalid token: " + err.Error(),
@@ -53,26 +51,124 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 额外验证token是否有效
-		if !tokenObj.Valid {
+		// 手动验证过期时间（双重保险）
+		if claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Token is invalid or expired",
+				"error": "Token has expired",
 			})
 			c.Abort()
 			return
 		}
 
-		// 手动验证过期时间（双重保险）
-		if claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
+		// 检查令牌是否已被作废（登出、修改密码、禁用账号等）
+		if claims.ID == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Token has expired",
+				"error": "Token is missing jti, please login again",
+			})
+			c.Abort()
+			return
+		}
+		revoked, err := service.IsTokenRevoked(claims.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to check token status",
+			})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token has been revoked",
 			})
 			c.Abort()
 			return
 		}
 
-		// 将用户ID存入上下文
+		// 记录会话的最近使用时间
+		if claims.SessionID != 0 {
+			if err := service.TouchSession(claims.SessionID, c.ClientIP()); err != nil {
+				fmt.Printf("更新会话使用时间失败: %v\n", err)
+			}
+		}
+
+		// 旧版本签发的令牌没有角色信息，按最低权限处理
+		role := claims.Role
+		if !model.IsValidRole(role) {
+			role = model.RoleReader
+		}
+
+		// 将用户ID和角色存入上下文
 		c.Set("user_id", claims.UserID)
+		c.Set("role", role)
+		c.Set("jti", claims.ID)
+		c.Set("session_id", claims.SessionID)
+		if claims.ExpiresAt != nil {
+			c.Set("token_expires_at", claims.ExpiresAt.Time)
+		}
+
+		// 模拟登录令牌：记录实际操作的管理员，并审计每个请求
+		if claims.Act != nil {
+			// 签发令牌的管理员被降级或禁用后，模拟令牌立即失效
+			if err := service.CheckImpersonator(claims.Act.UserID); err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": "Invalid impersonation token: " + err.Error(),
+				})
+				c.Abort()
+				return
+			}
+			c.Set("impersonator_id", claims.Act.UserID)
+			c.Next()
+			service.RecordImpersonatedRequest(claims.Act.UserID, claims.UserID, claims.ID,
+				c.Request.Method, c.Request.URL.Path, c.Writer.Status(), c.ClientIP())
+			return
+		}
 		c.Next()
 	}
 }
+
+// authenticateAccessToken 校验个人访问令牌，令牌需拥有 scopes 中的任一权限范围
+func authenticateAccessToken(c *gin.Context, tokenString string, scopes []string) {
+	if len(scopes) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Personal access tokens are not allowed for this endpoint",
+		})
+		c.Abort()
+		return
+	}
+
+	accessToken, user, err := service.AuthenticatePersonalAccessToken(tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid token: " + err.Error(),
+		})
+		c.Abort()
+		return
+	}
+
+	allowed := false
+	for _, scope := range scopes {
+		if accessToken.HasScope(scope) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Token lacks required scope",
+		})
+		c.Abort()
+		return
+	}
+
+	role := user.Role
+	if !model.IsValidRole(role) {
+		role = model.RoleReader
+	}
+
+	// 将用户ID、角色和令牌权限范围存入上下文
+	c.Set("user_id", user.ID)
+	c.Set("role", role)
+	c.Set("access_token_id", accessToken.ID)
+	c.Set("token_scopes", accessToken.ScopeList())
+	c.Next()
+}
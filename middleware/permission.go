@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// RequirePermission 权限校验中间件，需在 AuthMiddleware 之后使用
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		if role == "" {
+			utils.Error(c, http.StatusUnauthorized, "请先登录")
+			c.Abort()
+			return
+		}
+
+		if !model.HasPermission(role, permission) {
+			utils.Error(c, http.StatusForbidden, "权限不足")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRoles 角色校验中间件，只允许指定角色访问，需在 AuthMiddleware 之后使用
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		if role == "" {
+			utils.Error(c, http.StatusUnauthorized, "请先登录")
+			c.Abort()
+			return
+		}
+
+		for _, r := range roles {
+			if r == role {
+				c.Next()
+				return
+			}
+		}
+
+		utils.Error(c, http.StatusForbidden, "权限不足")
+		c.Abort()
+	}
+}
+
+// RequireScope 权限范围校验中间件：使用个人访问令牌时必须拥有指定权限范围，JWT登录不受限制
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("token_scopes")
+		if !exists {
+			c.Next()
+			return
+		}
+
+		scopes, _ := value.([]string)
+		for _, s := range scopes {
+			if s == scope {
+				c.Next()
+				return
+			}
+		}
+
+		utils.Error(c, http.StatusForbidden, "访问令牌缺少权限范围: "+scope)
+		c.Abort()
+	}
+}
+
+// DenyImpersonation 禁止使用模拟登录令牌访问，用于修改密码、两步验证、令牌管理等敏感操作，需在 AuthMiddleware 之后使用
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetUint("impersonator_id") != 0 {
+			utils.Error(c, http.StatusForbidden, "模拟登录状态下不允许此操作")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
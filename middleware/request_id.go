@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"gin-blog-system/utils"
+	"github.com/gin-gonic/gin"
+	"regexp"
+)
+
+// RequestIDHeader 请求ID的请求头/响应头名称
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID 客户端传入的请求ID只接受常见字符，避免写入日志和审计记录的内容被伪造
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID 请求ID中间件：沿用客户端传入的合法 X-Request-ID，否则生成新的ID，
+// 存入上下文的 request_id 并写回响应头，用于关联日志和审计记录
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID, _ = utils.GenerateSecureToken(12)
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
@@ -1,27 +1,90 @@
 package model
 
 import (
+	"strconv"
 	"time"
 )
 
+// 文章状态（编辑流程：草稿 → 待审核 → 需修改/已通过 → 已发布 → 已归档）
+const (
+	ArticleStatusDraft            = 0 // 草稿
+	ArticleStatusPublished        = 1 // 已发布
+	ArticleStatusScheduled        = 2 // 定时发布，到达发布时间后由调度器发布
+	ArticleStatusSubmitted        = 3 // 已提交，等待审核
+	ArticleStatusChangesRequested = 4 // 审核要求修改
+	ArticleStatusApproved         = 5 // 审核通过，等待发布
+	ArticleStatusArchived         = 6 // 已归档（下线）
+)
+
+// articleStatusNames 文章状态的名称，用于API响应和错误信息
+var articleStatusNames = map[int]string{
+	ArticleStatusDraft:            "draft",
+	ArticleStatusPublished:        "published",
+	ArticleStatusScheduled:        "scheduled",
+	ArticleStatusSubmitted:        "submitted",
+	ArticleStatusChangesRequested: "changes_requested",
+	ArticleStatusApproved:         "approved",
+	ArticleStatusArchived:         "archived",
+}
+
+// ArticleStatusName 返回文章状态的名称
+func ArticleStatusName(status int) string {
+	if name, ok := articleStatusNames[status]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseArticleStatus 根据状态名称或数字查找文章状态
+func ParseArticleStatus(value string) (int, bool) {
+	for status, name := range articleStatusNames {
+		if value == name || value == strconv.Itoa(status) {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+// 文章内容格式
+const (
+	ContentFormatMarkdown = "markdown" // Markdown（默认）
+	ContentFormatHTML     = "html"     // HTML，输出前按白名单净化
+	ContentFormatPlain    = "plain"    // 纯文本
+)
+
+// IsValidContentFormat 检查内容格式是否合法
+func IsValidContentFormat(format string) bool {
+	return format == ContentFormatMarkdown || format == ContentFormatHTML || format == ContentFormatPlain
+}
+
 // Article 文章模型
 type Article struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	Title        string    `gorm:"not null" json:"title"`
-	Content      string    `gorm:"type:text" json:"content"`
-	Summary      string    `gorm:"type:text" json:"summary"`              // 文章摘要
-	Cover        string    `json:"cover"`                                 // 封面图片URL
-	Status       int       `gorm:"default:1" json:"status"`               // 1-发布, 0-草稿
-	ViewCount    int       `gorm:"default:0" json:"view_count"`           // 浏览计数
-	LikeCount    int       `gorm:"default:0" json:"like_count"`           // 点赞计数
-	CommentCount int       `gorm:"default:0" json:"comment_count"`        // 评论计数
-	UserID       uint      `json:"user_id"`                               // 作者ID
-	User         User      `gorm:"foreignKey:UserID" json:"user"`         // 关联用户
-	CategoryID   uint      `json:"category_id"`                           // 分类ID
-	Category     Category  `gorm:"foreignKey:CategoryID" json:"category"` // 关联分类
-	Tags         []Tag     `gorm:"many2many:article_tags;" json:"tags"`   // 关联标签
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Title         string     `gorm:"not null" json:"title"`
+	Slug          string     `gorm:"size:191;uniqueIndex" json:"slug"` // URL别名，根据标题自动生成
+	Content       string     `gorm:"type:text" json:"content"`
+	ContentFormat string     `gorm:"size:16;default:markdown" json:"content_format"` // 内容格式：markdown/html/plain
+	ContentHTML   string     `gorm:"type:mediumtext" json:"content_html"`            // 渲染并净化后的HTML，内容或格式变化时重新生成
+	RenderVersion int        `gorm:"default:0" json:"-"`                             // 生成 content_html 时的渲染规则版本
+	Summary       string     `gorm:"type:text" json:"summary"`                       // 文章摘要
+	Cover         string     `json:"cover"`                                          // 封面图片URL
+	Status        int        `gorm:"default:0;index" json:"status"`                  // 文章状态，见 ArticleStatus 常量
+	PublishAt     *time.Time `gorm:"index" json:"publish_at"`                        // 定时发布时间
+	UnpublishAt   *time.Time `gorm:"index" json:"unpublish_at"`                      // 定时下线时间，到达后文章归档
+	ViewCount     int        `gorm:"default:0" json:"view_count"`                    // 浏览计数
+	LikeCount     int        `gorm:"default:0" json:"like_count"`                    // 点赞计数
+	CommentCount  int        `gorm:"default:0" json:"comment_count"`                 // 评论计数
+	UserID        uint       `json:"user_id"`                                        // 作者ID
+	User          User       `gorm:"foreignKey:UserID" json:"user"`                  // 关联用户
+	ReviewerID    uint       `gorm:"index" json:"reviewer_id"`                       // 指定的审核人ID，0 表示未指定
+	CategoryID    uint       `json:"category_id"`                                    // 分类ID
+	Category      Category   `gorm:"foreignKey:CategoryID" json:"category"`          // 关联分类
+	Tags          []Tag      `gorm:"many2many:article_tags;" json:"tags"`            // 关联标签
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Authors 署名作者（包括主作者），需按 Position 排序预加载
+	Authors []ArticleAuthor `gorm:"foreignKey:ArticleID" json:"authors,omitempty"`
 }
 
 // TableName 指定表名
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+)
+
+// 文章署名角色
+const (
+	AuthorRoleAuthor     = "author"     // 主作者，即文章的 UserID，每篇文章只有一位
+	AuthorRoleCoAuthor   = "co-author"  // 合著者
+	AuthorRoleEditor     = "editor"     // 编辑
+	AuthorRoleTranslator = "translator" // 译者
+)
+
+// IsValidAuthorRole 检查署名角色是否合法
+func IsValidAuthorRole(role string) bool {
+	switch role {
+	case AuthorRoleAuthor, AuthorRoleCoAuthor, AuthorRoleEditor, AuthorRoleTranslator:
+		return true
+	}
+	return false
+}
+
+// ArticleAuthor 文章署名（多位作者和贡献者），署名的用户都可以编辑文章
+type ArticleAuthor struct {
+	ArticleID uint      `gorm:"primaryKey;autoIncrement:false" json:"article_id"`
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false;index" json:"user_id"`
+	User      User      `gorm:"foreignKey:UserID" json:"user"` // 关联用户
+	Role      string    `gorm:"size:20;not null" json:"role"`  // 署名角色，见 AuthorRole 常量
+	Position  int       `gorm:"not null" json:"position"`      // 展示顺序，从 0 开始
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (ArticleAuthor) TableName() string {
+	return "article_authors"
+}
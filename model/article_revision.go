@@ -0,0 +1,34 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ArticleRevision 文章修订记录：每次保存文章时记录一份完整快照
+type ArticleRevision struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ArticleID     uint      `gorm:"not null;uniqueIndex:idx_article_revision" json:"article_id"`
+	Revision      int       `gorm:"not null;uniqueIndex:idx_article_revision" json:"revision"` // 修订号，每篇文章从1开始递增
+	Title         string    `gorm:"not null" json:"title"`
+	Content       string    `gorm:"type:text" json:"content"`
+	ContentFormat string    `gorm:"size:16" json:"content_format"`
+	Summary       string    `gorm:"type:text" json:"summary"`
+	TagIDs        string    `gorm:"size:1000" json:"-"`     // 标签ID列表（JSON）
+	EditorID      uint      `gorm:"index" json:"editor_id"` // 保存该修订的用户ID
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ArticleRevision) TableName() string {
+	return "article_revisions"
+}
+
+// TagIDList 解析修订中保存的标签ID列表
+func (r *ArticleRevision) TagIDList() []uint {
+	ids := []uint{}
+	if r.TagIDs != "" {
+		_ = json.Unmarshal([]byte(r.TagIDs), &ids)
+	}
+	return ids
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// ArticleSearchDocument 文章的搜索文档，只收录已发布的文章
+// 标签和分类名称一并写入，正文为渲染后去除标签的纯文本
+type ArticleSearchDocument struct {
+	ArticleID uint      `gorm:"primaryKey;autoIncrement:false" json:"article_id"`
+	Title     string    `gorm:"type:text" json:"title"`
+	Summary   string    `gorm:"type:text" json:"summary"`
+	Body      string    `gorm:"type:mediumtext" json:"body"`
+	Tags      string    `gorm:"type:text" json:"tags"`     // 标签名称，以空格分隔
+	Category  string    `gorm:"type:text" json:"category"` // 分类名称
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ArticleSearchDocument) TableName() string {
+	return "article_search_documents"
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+)
+
+// AuditEvent 审计日志：记录管理操作和安全相关事件
+type AuditEvent struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	ActorID        uint      `gorm:"index" json:"actor_id"`                             // 操作者ID，0表示未登录或系统任务
+	ImpersonatorID uint      `gorm:"default:0" json:"impersonator_id"`                  // 模拟登录时实际操作的管理员ID
+	Action         string    `gorm:"size:50;not null;index" json:"action"`              // 操作，如 article.update、auth.login
+	TargetType     string    `gorm:"size:30;index:idx_audit_target" json:"target_type"` // 操作对象类型
+	TargetID       uint      `gorm:"index:idx_audit_target" json:"target_id"`           // 操作对象ID
+	Changes        string    `gorm:"type:text" json:"changes"`                          // 变更内容（JSON），字段名到 before/after 的映射
+	IP             string    `gorm:"size:64" json:"ip"`                                 // 客户端IP
+	RequestID      string    `gorm:"size:64;index" json:"request_id"`                   // 请求ID，对应响应头 X-Request-ID
+	CreatedAt      time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
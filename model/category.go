@@ -8,6 +8,7 @@ import (
 type Category struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
 	Name        string    `gorm:"not null;size:100" json:"name"`
+	Slug        string    `gorm:"size:191;uniqueIndex" json:"slug"` // URL别名，根据名称自动生成
 	Description string    `gorm:"type:text" json:"description"`
 	Status      int       `gorm:"default:1" json:"status"`               // 1-启用, 0-禁用
 	Articles    []Article `gorm:"foreignKey:CategoryID" json:"articles"` // 关联文章
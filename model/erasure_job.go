@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// 账号注销任务状态
+const (
+	ErasureStatusPending   = "pending"   // 等待执行
+	ErasureStatusRunning   = "running"   // 执行中
+	ErasureStatusCompleted = "completed" // 已完成
+	ErasureStatusFailed    = "failed"    // 执行失败
+)
+
+// 注销账号时对其文章的处理策略
+const (
+	ErasureArticleReassign = "reassign" // 转移到匿名账号
+	ErasureArticleDelete   = "delete"   // 删除
+)
+
+// ErasureJob 账号注销（个人数据删除）任务，在后台异步执行
+type ErasureJob struct {
+	ID            uint       `gorm:"primaryKey" json:"-"`
+	JobID         string     `gorm:"size:32;not null;uniqueIndex" json:"job_id"` // 对外公开的任务ID，用于查询进度
+	UserID        uint       `gorm:"not null;index" json:"-"`                    // 被注销的用户ID
+	RequestedBy   uint       `json:"-"`                                          // 发起人ID（本人或管理员）
+	ArticlePolicy string     `gorm:"size:20;not null" json:"article_policy"`     // 文章处理策略：reassign/delete
+	Status        string     `gorm:"size:20;not null;index" json:"status"`       // 任务状态
+	Error         string     `gorm:"type:text" json:"error,omitempty"`           // 失败原因
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ErasureJob) TableName() string {
+	return "erasure_jobs"
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// Follow 用户关注关系
+type Follow struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	FollowerID uint      `gorm:"not null;uniqueIndex:idx_follower_followee" json:"follower_id"`       // 关注者ID
+	FolloweeID uint      `gorm:"not null;uniqueIndex:idx_follower_followee;index" json:"followee_id"` // 被关注者ID
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Follow) TableName() string {
+	return "follows"
+}
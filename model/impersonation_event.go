@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+)
+
+// ImpersonationEvent 模拟登录审计记录：管理员签发模拟令牌以及使用模拟令牌发起的每个请求
+type ImpersonationEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	AdminID   uint      `gorm:"not null;index" json:"admin_id"` // 实际操作的管理员ID
+	UserID    uint      `gorm:"not null;index" json:"user_id"`  // 被模拟的用户ID
+	JTI       string    `gorm:"size:64;index" json:"jti"`       // 模拟令牌的 jti
+	Action    string    `gorm:"size:20;not null" json:"action"` // start（签发令牌）/ request（发起请求）
+	Method    string    `gorm:"size:10" json:"method"`          // 请求方法
+	Path      string    `gorm:"size:255" json:"path"`           // 请求路径
+	Status    int       `json:"status"`                         // 响应状态码
+	IP        string    `gorm:"size:64" json:"ip"`              // 客户端IP
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ImpersonationEvent) TableName() string {
+	return "impersonation_events"
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// InviteCode 注册邀请码，只保存哈希，注册模式为 invite 时使用
+type InviteCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	CodeHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"` // 邀请码的SHA-256哈希
+	Prefix    string     `gorm:"size:16" json:"prefix"`                 // 邀请码前几位明文，便于管理员识别
+	Note      string     `gorm:"size:255" json:"note"`                  // 备注
+	MaxUses   int        `gorm:"not null;default:1" json:"max_uses"`    // 最大使用次数
+	UseCount  int        `gorm:"not null;default:0" json:"use_count"`   // 已使用次数
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`                  // 过期时间，为空表示永不过期
+	CreatedBy uint       `json:"created_by"`                            // 创建者（管理员）ID
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`                  // 作废时间
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (InviteCode) TableName() string {
+	return "invite_codes"
+}
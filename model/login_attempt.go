@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+)
+
+// LoginAttempt 登录尝试记录，用于安全审计
+type LoginAttempt struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"index" json:"user_id"`           // 匹配到的用户ID，用户不存在时为0
+	Username  string    `gorm:"size:100;index" json:"username"` // 登录时提交的用户名或邮箱
+	IP        string    `gorm:"size:64;index" json:"ip"`        // 客户端IP
+	UserAgent string    `gorm:"size:255" json:"user_agent"`     // 客户端User-Agent
+	Success   bool      `json:"success"`                        // 是否登录成功
+	Reason    string    `gorm:"size:50" json:"reason"`          // 失败原因
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// LoginFailure 登录失败计数（按账号或IP），用于数据库存储的失败计数器
+type LoginFailure struct {
+	Key          string     `gorm:"primaryKey;column:counter_key;size:191" json:"key"` // 计数键，如 account:1、ip:127.0.0.1
+	Failures     int        `gorm:"default:0" json:"failures"`                         // 连续失败次数
+	LastFailedAt time.Time  `json:"last_failed_at"`                                    // 最近一次失败时间
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`                            // 锁定截止时间
+}
+
+// TableName 指定表名
+func (LoginFailure) TableName() string {
+	return "login_failures"
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+)
+
+// OAuthState 第三方登录授权请求的状态，用于校验 state/nonce 和保存 PKCE 校验码
+type OAuthState struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	StateHash    string     `gorm:"size:64;not null;uniqueIndex" json:"-"` // state 参数的SHA-256哈希
+	BindingHash  string     `gorm:"size:64" json:"-"`                      // 浏览器绑定 Cookie 的SHA-256哈希，回调时必须携带同一 Cookie
+	Provider     string     `gorm:"size:50;not null" json:"provider"`      // 身份提供方名称
+	Nonce        string     `gorm:"size:64;not null" json:"-"`             // ID Token 中应包含的 nonce
+	CodeVerifier string     `gorm:"size:128;not null" json:"-"`            // PKCE 校验码
+	UserID       uint       `json:"user_id"`                               // 非0时表示为已登录用户绑定身份
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`            // 过期时间
+	UsedAt       *time.Time `json:"used_at,omitempty"`                     // 使用时间，使用后即失效
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (OAuthState) TableName() string {
+	return "oauth_states"
+}
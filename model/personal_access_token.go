@@ -0,0 +1,67 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// PersonalAccessTokenPrefix 个人访问令牌的固定前缀，便于识别和密钥扫描
+const PersonalAccessTokenPrefix = "gbp_"
+
+// 个人访问令牌权限范围
+const (
+	ScopeArticlesRead  = "articles:read"  // 读取文章
+	ScopeArticlesWrite = "articles:write" // 发布、编辑、删除文章
+	ScopeCommentsWrite = "comments:write" // 发表、删除评论
+	ScopeUploadsWrite  = "uploads:write"  // 上传文件
+)
+
+// validScopes 所有合法的权限范围
+var validScopes = []string{ScopeArticlesRead, ScopeArticlesWrite, ScopeCommentsWrite, ScopeUploadsWrite}
+
+// IsValidScope 检查权限范围是否合法
+func IsValidScope(scope string) bool {
+	for _, s := range validScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalAccessToken 个人访问令牌模型，用于CI等自动化场景，只保存令牌哈希
+type PersonalAccessToken struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`         // 所属用户ID
+	Name       string     `gorm:"size:100;not null" json:"name"`         // 令牌名称
+	Prefix     string     `gorm:"size:16;index" json:"prefix"`           // 令牌前几位明文，便于用户识别
+	TokenHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"` // 令牌的SHA-256哈希
+	Scopes     string     `gorm:"size:255" json:"scopes"`                // 权限范围，逗号分隔
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`                  // 过期时间，为空表示永不过期
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`                // 最近使用时间
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`                  // 撤销时间
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// ScopeList 返回权限范围列表
+func (t *PersonalAccessToken) ScopeList() []string {
+	if t.Scopes == "" {
+		return nil
+	}
+	return strings.Split(t.Scopes, ",")
+}
+
+// HasScope 检查令牌是否拥有指定权限范围
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// RefreshToken 刷新令牌模型
+// 同一次登录派生出的所有刷新令牌属于同一个令牌族（FamilyID），
+// 每次刷新都会轮换出新的令牌，旧令牌被再次使用时整族作废
+type RefreshToken struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`           // 所属用户ID
+	FamilyID  string     `gorm:"size:64;not null;index" json:"family_id"` // 令牌族ID
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex" json:"-"`   // 令牌的SHA-256哈希
+	AccessJTI string     `gorm:"size:64;index" json:"-"`                  // 同时签发的访问令牌ID
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`              // 过期时间
+	UsedAt    *time.Time `json:"used_at,omitempty"`                       // 被轮换的时间
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`                    // 作废时间
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
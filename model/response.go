@@ -1,47 +1,130 @@
 package model
 
 import (
+	"encoding/json"
 	"gin-blog-system/utils"
 	"strings"
 )
 
 // ArticleResponse 用于API响应的文章结构体
 type ArticleResponse struct {
-	ID           uint             `json:"id"`
-	Title        string           `json:"title"`
-	Content      string           `json:"content"`
-	Summary      string           `json:"summary"`
-	Cover        string           `json:"cover"`
-	Status       int              `json:"status"`
-	ViewCount    int              `json:"view_count"`
-	LikeCount    int              `json:"like_count"`
-	CommentCount int              `json:"comment_count"` // 新增评论计数
-	UserID       uint             `json:"user_id"`
-	User         UserResponse     `json:"user"`
-	CategoryID   uint             `json:"category_id"`
-	Category     CategoryResponse `json:"category"`
-	TagIDs       []uint           `json:"tag_ids,omitempty"`
-	Tags         []TagResponse    `json:"tags"`
-	CreatedAt    utils.CustomTime `json:"created_at"` // 使用自定义时间格式
-	UpdatedAt    utils.CustomTime `json:"updated_at"` // 使用自定义时间格式
+	ID            uint               `json:"id"`
+	Title         string             `json:"title"`
+	Slug          string             `json:"slug"`
+	Content       string             `json:"content"`        // 原始内容
+	ContentFormat string             `json:"content_format"` // 内容格式：markdown/html/plain
+	ContentHTML   string             `json:"content_html"`   // 渲染并净化后的HTML
+	Summary       string             `json:"summary"`
+	Cover         string             `json:"cover"`
+	Status        int                `json:"status"`
+	State         string             `json:"state"`        // 状态名称：draft/submitted/changes_requested/approved/scheduled/published/archived
+	ReviewerID    uint               `json:"reviewer_id"`  // 指定的审核人ID
+	PublishAt     *utils.CustomTime  `json:"publish_at"`   // 定时发布时间
+	UnpublishAt   *utils.CustomTime  `json:"unpublish_at"` // 定时下线时间
+	ViewCount     int                `json:"view_count"`
+	LikeCount     int                `json:"like_count"`
+	CommentCount  int                `json:"comment_count"` // 新增评论计数
+	UserID        uint               `json:"user_id"`
+	User          PublicUserResponse `json:"user"` // 主作者，只包含公开信息
+	CategoryID    uint               `json:"category_id"`
+	Category      CategoryResponse   `json:"category"`
+	TagIDs        []uint             `json:"tag_ids,omitempty"`
+	Tags          []TagResponse      `json:"tags"`
+	Authors       []AuthorResponse   `json:"authors"` // 署名作者，主作者同时保留在 user 字段中
+	Series        *ArticleSeries     `json:"series,omitempty"`
+	CreatedAt     utils.CustomTime   `json:"created_at"` // 使用自定义时间格式
+	UpdatedAt     utils.CustomTime   `json:"updated_at"` // 使用自定义时间格式
+}
+
+// AuthorResponse 文章的署名作者
+type AuthorResponse struct {
+	UserID   uint               `json:"user_id"`
+	User     PublicUserResponse `json:"user"`
+	Role     string             `json:"role"`     // 署名角色：author/co-author/editor/translator
+	Position int                `json:"position"` // 展示顺序
+}
+
+// SeriesPartResponse 系列中的一篇文章
+type SeriesPartResponse struct {
+	ID       uint   `json:"id"`
+	Title    string `json:"title"`
+	Slug     string `json:"slug"`
+	Summary  string `json:"summary"`
+	State    string `json:"state"`
+	Position int    `json:"position"` // 在系列中的序号，从 1 开始
+}
+
+// ArticleSeries 文章所属的系列以及前一篇、后一篇（只链接已发布的文章）
+type ArticleSeries struct {
+	ID       uint                `json:"id"`
+	Title    string              `json:"title"`
+	Position int                 `json:"position"` // 在系列中的序号，从 1 开始
+	Total    int                 `json:"total"`    // 系列中的文章数
+	Prev     *SeriesPartResponse `json:"prev"`
+	Next     *SeriesPartResponse `json:"next"`
+}
+
+// SeriesResponse 用于API响应的系列结构体，列表中不返回文章
+type SeriesResponse struct {
+	ID           uint                 `json:"id"`
+	Title        string               `json:"title"`
+	Description  string               `json:"description"`
+	Cover        string               `json:"cover"`
+	UserID       uint                 `json:"user_id"`
+	User         PublicUserResponse   `json:"user"`
+	ArticleCount int                  `json:"article_count"` // 已发布的文章数
+	Articles     []SeriesPartResponse `json:"articles,omitempty"`
+	CreatedAt    utils.CustomTime     `json:"created_at"`
+	UpdatedAt    utils.CustomTime     `json:"updated_at"`
 }
 
 // UserResponse 用于API响应的用户结构体
 type UserResponse struct {
+	ID               uint             `json:"id"`
+	Username         string           `json:"username"`
+	Nickname         string           `json:"nickname"`
+	Email            string           `json:"email"`
+	EmailVerified    bool             `json:"email_verified"`
+	TwoFactorEnabled *bool            `json:"two_factor_enabled,omitempty"` // 仅本人和管理员可见
+	Avatar           string           `json:"avatar"`
+	Bio              string           `json:"bio"`
+	Role             string           `json:"role"`
+	Status           int              `json:"status"`
+	CreatedAt        utils.CustomTime `json:"created_at"` // 使用自定义时间格式
+	UpdatedAt        utils.CustomTime `json:"updated_at"` // 使用自定义时间格式
+}
+
+// PublicUserResponse 用于公开主页的用户结构体（不包含邮箱、角色等非公开信息）
+type PublicUserResponse struct {
 	ID        uint             `json:"id"`
 	Username  string           `json:"username"`
 	Nickname  string           `json:"nickname"`
-	Email     string           `json:"email"`
 	Avatar    string           `json:"avatar"`
-	Status    int              `json:"status"`
+	Bio       string           `json:"bio"`
 	CreatedAt utils.CustomTime `json:"created_at"` // 使用自定义时间格式
-	UpdatedAt utils.CustomTime `json:"updated_at"` // 使用自定义时间格式
+}
+
+// UserStatsResponse 用户的文章统计（只统计已发布的文章）
+type UserStatsResponse struct {
+	ArticleCount int64 `json:"article_count"`
+	ViewCount    int64 `json:"view_count"`
+	LikeCount    int64 `json:"like_count"`
+	CommentCount int64 `json:"comment_count"`
+}
+
+// UserProfileResponse 用户公开主页
+type UserProfileResponse struct {
+	User           PublicUserResponse `json:"user"`
+	Stats          UserStatsResponse  `json:"stats"`
+	FollowerCount  int64              `json:"follower_count"`  // 粉丝数
+	FollowingCount int64              `json:"following_count"` // 关注数
 }
 
 // CategoryResponse 用于API响应的分类结构体
 type CategoryResponse struct {
 	ID          uint             `json:"id"`
 	Name        string           `json:"name"`
+	Slug        string           `json:"slug"`
 	Description string           `json:"description"`
 	Status      int              `json:"status"`
 	CreatedAt   utils.CustomTime `json:"created_at"` // 使用自定义时间格式
@@ -52,6 +135,7 @@ type CategoryResponse struct {
 type TagResponse struct {
 	ID        uint             `json:"id"`
 	Name      string           `json:"name"`
+	Slug      string           `json:"slug"`
 	Color     string           `json:"color"`
 	Status    int              `json:"status"`
 	CreatedAt utils.CustomTime `json:"created_at"` // 使用自定义时间格式
@@ -60,16 +144,107 @@ type TagResponse struct {
 
 // CommentResponse 用于API响应的评论结构体
 type CommentResponse struct {
-	ID        uint             `json:"id"`
-	Content   string           `json:"content"`
-	UserID    uint             `json:"user_id"`
-	User      UserResponse     `json:"user"`
-	ArticleID uint             `json:"article_id"`
-	ParentID  *uint            `json:"parent_id,omitempty"`
-	Parent    *CommentResponse `json:"parent,omitempty"`
-	Status    int              `json:"status"`
-	CreatedAt utils.CustomTime `json:"created_at"` // 使用自定义时间格式
-	UpdatedAt utils.CustomTime `json:"updated_at"` // 使用自定义时间格式
+	ID        uint               `json:"id"`
+	Content   string             `json:"content"`
+	UserID    uint               `json:"user_id"`
+	User      PublicUserResponse `json:"user"` // 评论者，只包含公开信息
+	ArticleID uint               `json:"article_id"`
+	ParentID  *uint              `json:"parent_id,omitempty"`
+	Parent    *CommentResponse   `json:"parent,omitempty"`
+	Status    int                `json:"status"`
+	CreatedAt utils.CustomTime   `json:"created_at"` // 使用自定义时间格式
+	UpdatedAt utils.CustomTime   `json:"updated_at"` // 使用自定义时间格式
+}
+
+// SessionResponse 用于API响应的登录会话结构体
+type SessionResponse struct {
+	ID         uint             `json:"id"`
+	UserID     uint             `json:"user_id"`
+	UserAgent  string           `json:"user_agent"`
+	IP         string           `json:"ip"`
+	Current    bool             `json:"current"` // 是否为当前请求所用的会话
+	CreatedAt  utils.CustomTime `json:"created_at"`
+	LastUsedAt utils.CustomTime `json:"last_used_at"`
+	ExpiresAt  utils.CustomTime `json:"expires_at"`
+}
+
+// PersonalAccessTokenResponse 用于API响应的个人访问令牌结构体
+type PersonalAccessTokenResponse struct {
+	ID         uint              `json:"id"`
+	Name       string            `json:"name"`
+	Prefix     string            `json:"prefix"`
+	Scopes     []string          `json:"scopes"`
+	Token      string            `json:"token,omitempty"` // 令牌明文，仅在创建时返回一次
+	ExpiresAt  *utils.CustomTime `json:"expires_at"`
+	LastUsedAt *utils.CustomTime `json:"last_used_at"`
+	CreatedAt  utils.CustomTime  `json:"created_at"`
+}
+
+// InviteCodeResponse 用于API响应的邀请码结构体
+type InviteCodeResponse struct {
+	ID        uint              `json:"id"`
+	Prefix    string            `json:"prefix"`
+	Code      string            `json:"code,omitempty"` // 邀请码明文，仅在创建时返回一次
+	Note      string            `json:"note"`
+	MaxUses   int               `json:"max_uses"`
+	UseCount  int               `json:"use_count"`
+	ExpiresAt *utils.CustomTime `json:"expires_at"`
+	RevokedAt *utils.CustomTime `json:"revoked_at"`
+	CreatedBy uint              `json:"created_by"`
+	CreatedAt utils.CustomTime  `json:"created_at"`
+}
+
+// ArticleRevisionResponse 用于API响应的文章修订结构体，列表中不返回正文
+type ArticleRevisionResponse struct {
+	ID            uint             `json:"id"`
+	ArticleID     uint             `json:"article_id"`
+	Revision      int              `json:"revision"`
+	Title         string           `json:"title"`
+	Content       string           `json:"content,omitempty"`
+	ContentFormat string           `json:"content_format"`
+	Summary       string           `json:"summary"`
+	TagIDs        []uint           `json:"tag_ids"`
+	EditorID      uint             `json:"editor_id"`
+	CreatedAt     utils.CustomTime `json:"created_at"`
+}
+
+// ReviewCommentResponse 用于API响应的审核意见结构体
+type ReviewCommentResponse struct {
+	ID        uint               `json:"id"`
+	ArticleID uint               `json:"article_id"`
+	UserID    uint               `json:"user_id"`
+	User      PublicUserResponse `json:"user"`
+	Action    string             `json:"action"`
+	Content   string             `json:"content"`
+	CreatedAt utils.CustomTime   `json:"created_at"`
+}
+
+// SearchHighlight 搜索结果中高亮关键词的片段，文本已转义，关键词用 <mark> 标出
+type SearchHighlight struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Content string `json:"content"` // 正文中第一个关键词附近的摘录
+}
+
+// SearchResultResponse 用于API响应的搜索结果结构体
+type SearchResultResponse struct {
+	ArticleResponse
+	Score     float64         `json:"score"` // 相关度，只用于同一次搜索的结果之间比较
+	Highlight SearchHighlight `json:"highlight"`
+}
+
+// AuditEventResponse 用于API响应的审计日志结构体
+type AuditEventResponse struct {
+	ID             uint             `json:"id"`
+	ActorID        uint             `json:"actor_id"`
+	ImpersonatorID uint             `json:"impersonator_id,omitempty"`
+	Action         string           `json:"action"`
+	TargetType     string           `json:"target_type"`
+	TargetID       uint             `json:"target_id"`
+	Changes        json.RawMessage  `json:"changes"` // 字段名到 {before, after} 的映射
+	IP             string           `json:"ip"`
+	RequestID      string           `json:"request_id"`
+	CreatedAt      utils.CustomTime `json:"created_at"`
 }
 
 // addStaticPrefix 为图片路径添加静态文件前缀
@@ -85,16 +260,52 @@ func addStaticPrefix(path string) string {
 	return "/static/" + path
 }
 
+// ConvertToUserResponse 将User模型转换为API响应结构体（过滤密码等敏感字段），用于本人和管理员接口
+func (u *User) ConvertToUserResponse() *UserResponse {
+	twoFactorEnabled := u.TwoFactorEnabled
+	return &UserResponse{
+		ID:               u.ID,
+		Username:         u.Username,
+		Nickname:         u.Nickname,
+		Email:            u.Email,
+		EmailVerified:    u.EmailVerified,
+		TwoFactorEnabled: &twoFactorEnabled,
+		Avatar:           addStaticPrefix(u.Avatar),
+		Bio:              u.Bio,
+		Role:             u.Role,
+		Status:           u.Status,
+		CreatedAt:        utils.CustomTime{Time: u.CreatedAt},
+		UpdatedAt:        utils.CustomTime{Time: u.UpdatedAt},
+	}
+}
+
+// ConvertToPublicUserResponse 将User模型转换为公开主页使用的响应结构体
+func (u *User) ConvertToPublicUserResponse() *PublicUserResponse {
+	return &PublicUserResponse{
+		ID:        u.ID,
+		Username:  u.Username,
+		Nickname:  u.Nickname,
+		Avatar:    addStaticPrefix(u.Avatar),
+		Bio:       u.Bio,
+		CreatedAt: utils.CustomTime{Time: u.CreatedAt},
+	}
+}
+
 // ConvertToArticleResponse 将Article模型转换为API响应结构体
 func (a *Article) ConvertToArticleResponse() *ArticleResponse {
 	response := &ArticleResponse{
-		ID:      a.ID,
-		Title:   a.Title,
-		Content: a.Content,
-		Summary: a.Summary,
+		ID:            a.ID,
+		Title:         a.Title,
+		Slug:          a.Slug,
+		Content:       a.Content,
+		ContentFormat: a.ContentFormat,
+		ContentHTML:   a.ContentHTML,
+		Summary:       a.Summary,
 		// 为图片路径添加静态文件前缀
 		Cover:        addStaticPrefix(a.Cover),
 		Status:       a.Status,
+		State:        ArticleStatusName(a.Status),
+		ReviewerID:   a.ReviewerID,
 		ViewCount:    a.ViewCount,
 		LikeCount:    a.LikeCount,
 		CommentCount: a.CommentCount, // 新增评论计数
@@ -104,6 +315,13 @@ func (a *Article) ConvertToArticleResponse() *ArticleResponse {
 		UpdatedAt:    utils.CustomTime{Time: a.UpdatedAt},
 	}
 
+	if a.PublishAt != nil {
+		response.PublishAt = &utils.CustomTime{Time: *a.PublishAt}
+	}
+	if a.UnpublishAt != nil {
+		response.UnpublishAt = &utils.CustomTime{Time: *a.UnpublishAt}
+	}
+
 	// 从关联的标签中提取标签ID
 	for _, tag := range a.Tags {
 		response.TagIDs = append(response.TagIDs, tag.ID)
@@ -111,23 +329,14 @@ func (a *Article) ConvertToArticleResponse() *ArticleResponse {
 
 	// 转换关联对象
 	if a.User.ID != 0 {
-		response.User = UserResponse{
-			ID:       a.User.ID,
-			Username: a.User.Username,
-			Nickname: a.User.Nickname,
-			Email:    a.User.Email,
-			// 为头像路径添加静态文件前缀
-			Avatar:    addStaticPrefix(a.User.Avatar),
-			Status:    a.User.Status,
-			CreatedAt: utils.CustomTime{Time: a.User.CreatedAt},
-			UpdatedAt: utils.CustomTime{Time: a.User.UpdatedAt},
-		}
+		response.User = *a.User.ConvertToPublicUserResponse()
 	}
 
 	if a.Category.ID != 0 {
 		response.Category = CategoryResponse{
 			ID:          a.Category.ID,
 			Name:        a.Category.Name,
+			Slug:        a.Category.Slug,
 			Description: a.Category.Description,
 			Status:      a.Category.Status,
 			CreatedAt:   utils.CustomTime{Time: a.Category.CreatedAt},
@@ -139,6 +348,7 @@ func (a *Article) ConvertToArticleResponse() *ArticleResponse {
 		tagResp := TagResponse{
 			ID:        tag.ID,
 			Name:      tag.Name,
+			Slug:      tag.Slug,
 			Color:     tag.Color,
 			Status:    tag.Status,
 			CreatedAt: utils.CustomTime{Time: tag.CreatedAt},
@@ -147,9 +357,31 @@ func (a *Article) ConvertToArticleResponse() *ArticleResponse {
 		response.Tags = append(response.Tags, tagResp)
 	}
 
+	// 署名作者，未预加载时只返回主作者
+	for _, author := range a.Authors {
+		response.Authors = append(response.Authors, *author.ConvertToAuthorResponse())
+	}
+	if len(response.Authors) == 0 && a.User.ID != 0 {
+		response.Authors = []AuthorResponse{{
+			UserID: a.UserID,
+			User:   *a.User.ConvertToPublicUserResponse(),
+			Role:   AuthorRoleAuthor,
+		}}
+	}
+
 	return response
 }
 
+// ConvertToAuthorResponse 将ArticleAuthor模型转换为API响应结构体
+func (a *ArticleAuthor) ConvertToAuthorResponse() *AuthorResponse {
+	return &AuthorResponse{
+		UserID:   a.UserID,
+		User:     *a.User.ConvertToPublicUserResponse(),
+		Role:     a.Role,
+		Position: a.Position,
+	}
+}
+
 // ConvertToCommentResponse 将Comment模型转换为API响应结构体
 func (c *Comment) ConvertToCommentResponse() *CommentResponse {
 	response := &CommentResponse{
@@ -162,18 +394,9 @@ func (c *Comment) ConvertToCommentResponse() *CommentResponse {
 		UpdatedAt: utils.CustomTime{Time: c.UpdatedAt},
 	}
 
-	// 转换关联对象（过滤敏感字段：密码）
+	// 转换关联对象（评论者只返回公开信息）
 	if c.User.ID != 0 {
-		response.User = UserResponse{
-			ID:        c.User.ID,
-			Username:  c.User.Username,
-			Nickname:  c.User.Nickname,
-			Email:     c.User.Email,
-			Avatar:    c.User.Avatar,
-			Status:    c.User.Status,
-			CreatedAt: utils.CustomTime{Time: c.User.CreatedAt},
-			UpdatedAt: utils.CustomTime{Time: c.User.UpdatedAt},
-		}
+		response.User = *c.User.ConvertToPublicUserResponse()
 	}
 
 	if c.ParentID != nil && c.Parent != nil && c.Parent.ID != 0 {
@@ -188,19 +411,131 @@ func (c *Comment) ConvertToCommentResponse() *CommentResponse {
 		}
 		// 转换父评论的关联对象
 		if c.Parent.User.ID != 0 {
-			parentResp.User = UserResponse{
-				ID:        c.Parent.User.ID,
-				Username:  c.Parent.User.Username,
-				Nickname:  c.Parent.User.Nickname,
-				Email:     c.Parent.User.Email,
-				Avatar:    c.Parent.User.Avatar,
-				Status:    c.Parent.User.Status,
-				CreatedAt: utils.CustomTime{Time: c.Parent.User.CreatedAt},
-				UpdatedAt: utils.CustomTime{Time: c.Parent.User.UpdatedAt},
-			}
+			parentResp.User = *c.Parent.User.ConvertToPublicUserResponse()
 		}
 		response.Parent = &parentResp
 	}
 
 	return response
 }
+
+// ConvertToSessionResponse 将Session模型转换为API响应结构体
+func (s *Session) ConvertToSessionResponse(currentSessionID uint) *SessionResponse {
+	return &SessionResponse{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		Current:    s.ID == currentSessionID,
+		CreatedAt:  utils.CustomTime{Time: s.CreatedAt},
+		LastUsedAt: utils.CustomTime{Time: s.LastUsedAt},
+		ExpiresAt:  utils.CustomTime{Time: s.ExpiresAt},
+	}
+}
+
+// ConvertToPersonalAccessTokenResponse 将PersonalAccessToken模型转换为API响应结构体
+func (t *PersonalAccessToken) ConvertToPersonalAccessTokenResponse() *PersonalAccessTokenResponse {
+	response := &PersonalAccessTokenResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Prefix:    t.Prefix,
+		Scopes:    t.ScopeList(),
+		CreatedAt: utils.CustomTime{Time: t.CreatedAt},
+	}
+	if t.ExpiresAt != nil {
+		response.ExpiresAt = &utils.CustomTime{Time: *t.ExpiresAt}
+	}
+	if t.LastUsedAt != nil {
+		response.LastUsedAt = &utils.CustomTime{Time: *t.LastUsedAt}
+	}
+	return response
+}
+
+// ConvertToInviteCodeResponse 将InviteCode模型转换为API响应结构体
+func (i *InviteCode) ConvertToInviteCodeResponse() *InviteCodeResponse {
+	response := &InviteCodeResponse{
+		ID:        i.ID,
+		Prefix:    i.Prefix,
+		Note:      i.Note,
+		MaxUses:   i.MaxUses,
+		UseCount:  i.UseCount,
+		CreatedBy: i.CreatedBy,
+		CreatedAt: utils.CustomTime{Time: i.CreatedAt},
+	}
+	if i.ExpiresAt != nil {
+		response.ExpiresAt = &utils.CustomTime{Time: *i.ExpiresAt}
+	}
+	if i.RevokedAt != nil {
+		response.RevokedAt = &utils.CustomTime{Time: *i.RevokedAt}
+	}
+	return response
+}
+
+// ConvertToAuditEventResponse 将AuditEvent模型转换为API响应结构体
+func (e *AuditEvent) ConvertToAuditEventResponse() *AuditEventResponse {
+	changes := json.RawMessage(e.Changes)
+	if !json.Valid(changes) {
+		changes = json.RawMessage("{}")
+	}
+	return &AuditEventResponse{
+		ID:             e.ID,
+		ActorID:        e.ActorID,
+		ImpersonatorID: e.ImpersonatorID,
+		Action:         e.Action,
+		TargetType:     e.TargetType,
+		TargetID:       e.TargetID,
+		Changes:        changes,
+		IP:             e.IP,
+		RequestID:      e.RequestID,
+		CreatedAt:      utils.CustomTime{Time: e.CreatedAt},
+	}
+}
+
+// ConvertToArticleRevisionResponse 将ArticleRevision模型转换为API响应结构体，withContent 为 false 时不返回正文
+func (r *ArticleRevision) ConvertToArticleRevisionResponse(withContent bool) *ArticleRevisionResponse {
+	response := &ArticleRevisionResponse{
+		ID:            r.ID,
+		ArticleID:     r.ArticleID,
+		Revision:      r.Revision,
+		Title:         r.Title,
+		ContentFormat: r.ContentFormat,
+		Summary:       r.Summary,
+		TagIDs:        r.TagIDList(),
+		EditorID:      r.EditorID,
+		CreatedAt:     utils.CustomTime{Time: r.CreatedAt},
+	}
+	if withContent {
+		response.Content = r.Content
+	}
+	return response
+}
+
+// ConvertToReviewCommentResponse 将ReviewComment模型转换为API响应结构体
+func (r *ReviewComment) ConvertToReviewCommentResponse() *ReviewCommentResponse {
+	return &ReviewCommentResponse{
+		ID:        r.ID,
+		ArticleID: r.ArticleID,
+		UserID:    r.UserID,
+		User:      *r.User.ConvertToPublicUserResponse(),
+		Action:    r.Action,
+		Content:   r.Content,
+		CreatedAt: utils.CustomTime{Time: r.CreatedAt},
+	}
+}
+
+// ConvertToSeriesResponse 将Series模型转换为API响应结构体（不包含文章）
+func (s *Series) ConvertToSeriesResponse() *SeriesResponse {
+	response := &SeriesResponse{
+		ID:          s.ID,
+		Title:       s.Title,
+		Description: s.Description,
+		Cover:       addStaticPrefix(s.Cover),
+		UserID:      s.UserID,
+		CreatedAt:   utils.CustomTime{Time: s.CreatedAt},
+		UpdatedAt:   utils.CustomTime{Time: s.UpdatedAt},
+	}
+	if s.User.ID != 0 {
+		response.User = *s.User.ConvertToPublicUserResponse()
+	}
+	return response
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// ReviewComment 文章审核意见，只对作者和审核人可见，与公开评论分开存储
+type ReviewComment struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ArticleID uint      `gorm:"not null;index" json:"article_id"`  // 所属文章ID
+	UserID    uint      `gorm:"not null" json:"user_id"`           // 发表意见的用户ID
+	User      User      `gorm:"foreignKey:UserID" json:"user"`     // 关联用户
+	Action    string    `gorm:"size:32" json:"action"`             // 随状态流转提交时为对应操作（如 request_changes），单独发表时为空
+	Content   string    `gorm:"type:text;not null" json:"content"` // 意见内容
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (ReviewComment) TableName() string {
+	return "review_comments"
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// RevokedToken 已作废的访问令牌（按 jti 记录）
+type RevokedToken struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	JTI       string    `gorm:"size:64;not null;uniqueIndex" json:"jti"` // 访问令牌ID
+	UserID    uint      `gorm:"index" json:"user_id"`                    // 所属用户ID
+	Reason    string    `gorm:"size:50" json:"reason"`                   // 作废原因
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"`                 // 令牌原过期时间，过期后记录可清理
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
@@ -0,0 +1,63 @@
+package model
+
+// 用户角色
+const (
+	RoleAdmin  = "admin"  // 管理员：拥有全部权限
+	RoleEditor = "editor" // 编辑：可以编辑、删除任何人的文章和评论，审核和发布文章
+	RoleAuthor = "author" // 作者：可以撰写文章并提交审核，只能编辑自己的文章
+	RoleReader = "reader" // 读者：只能浏览、点赞和评论
+)
+
+// DefaultRole 新注册用户的默认角色
+const DefaultRole = RoleAuthor
+
+// 权限标识
+const (
+	PermArticleCreate    = "article:create"     // 创建文章
+	PermArticleEditAny   = "article:edit_any"   // 编辑/删除任何人的文章
+	PermArticleReview    = "article:review"     // 审核文章（通过或要求修改）
+	PermArticlePublish   = "article:publish"    // 发布审核通过的文章
+	PermCommentDeleteAny = "comment:delete_any" // 删除任何人的评论
+	PermTaxonomyManage   = "taxonomy:manage"    // 管理分类和标签
+	PermUserManage       = "user:manage"        // 管理用户（修改角色等）
+)
+
+// rolePermissions 角色与权限的对应关系
+var rolePermissions = map[string][]string{
+	RoleAdmin: {
+		PermArticleCreate,
+		PermArticleEditAny,
+		PermArticleReview,
+		PermArticlePublish,
+		PermCommentDeleteAny,
+		PermTaxonomyManage,
+		PermUserManage,
+	},
+	RoleEditor: {
+		PermArticleCreate,
+		PermArticleEditAny,
+		PermArticleReview,
+		PermArticlePublish,
+		PermCommentDeleteAny,
+	},
+	RoleAuthor: {
+		PermArticleCreate,
+	},
+	RoleReader: {},
+}
+
+// IsValidRole 检查角色是否合法
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// HasPermission 检查角色是否拥有指定权限
+func HasPermission(role, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+// SchedulerLock 后台定时任务的数据库锁，多实例部署时同一时间只有一个实例执行任务
+// 锁以租约形式持有，持有者异常退出后租约到期即可被其他实例获取
+type SchedulerLock struct {
+	Name        string    `gorm:"primaryKey;size:64" json:"name"` // 任务名称
+	Owner       string    `gorm:"size:100;not null" json:"owner"` // 持有锁的实例标识
+	LockedUntil time.Time `gorm:"not null" json:"locked_until"`   // 租约到期时间
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SchedulerLock) TableName() string {
+	return "scheduler_locks"
+}
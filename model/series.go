@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+)
+
+// Series 文章系列（如多篇组成的教程），包含按顺序排列的文章
+type Series struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Title       string    `gorm:"not null;size:200" json:"title"`
+	Description string    `gorm:"type:text" json:"description"`
+	Cover       string    `json:"cover"`                         // 封面图片URL
+	UserID      uint      `gorm:"index" json:"user_id"`          // 创建者ID
+	User        User      `gorm:"foreignKey:UserID" json:"user"` // 关联用户
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Series) TableName() string {
+	return "series"
+}
+
+// SeriesArticle 系列中的文章及其顺序，一篇文章最多属于一个系列
+type SeriesArticle struct {
+	ArticleID uint    `gorm:"primaryKey;autoIncrement:false" json:"article_id"`
+	SeriesID  uint    `gorm:"not null;index" json:"series_id"`
+	Position  int     `gorm:"not null" json:"position"` // 排序位置，从 1 开始
+	Article   Article `gorm:"foreignKey:ArticleID" json:"-"`
+}
+
+// TableName 指定表名
+func (SeriesArticle) TableName() string {
+	return "series_articles"
+}
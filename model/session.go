@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+// Session 登录会话模型，每次登录对应一个会话（即一个刷新令牌族）
+type Session struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`         // 所属用户ID
+	FamilyID   string     `gorm:"size:64;not null;uniqueIndex" json:"-"` // 对应的刷新令牌族ID
+	UserAgent  string     `gorm:"size:255" json:"user_agent"`            // 登录设备的User-Agent
+	IP         string     `gorm:"size:64" json:"ip"`                     // 最近使用的客户端IP
+	LastUsedAt time.Time  `json:"last_used_at"`                          // 最近使用时间
+	ExpiresAt  time.Time  `json:"expires_at"`                            // 会话过期时间（随刷新令牌延长）
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`                  // 注销时间
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Session) TableName() string {
+	return "sessions"
+}
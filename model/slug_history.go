@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+// SlugHistory 历史 slug：文章、分类、标签修改 slug 后，旧 slug 仍可访问并跳转到新地址
+type SlugHistory struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TargetType string    `gorm:"size:20;not null;uniqueIndex:idx_slug_history_target_slug" json:"target_type"` // 对象类型：article/category/tag
+	TargetID   uint      `gorm:"not null;index" json:"target_id"`                                              // 对象ID
+	Slug       string    `gorm:"size:191;not null;uniqueIndex:idx_slug_history_target_slug" json:"slug"`       // 旧 slug
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (SlugHistory) TableName() string {
+	return "slug_histories"
+}
@@ -8,6 +8,7 @@ import (
 type Tag struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	Name      string    `gorm:"not null;size:50" json:"name"`
+	Slug      string    `gorm:"size:191;uniqueIndex" json:"slug"`        // URL别名，根据名称自动生成
 	Color     string    `gorm:"size:20" json:"color"`                    // 标签颜色
 	Status    int       `gorm:"default:1" json:"status"`                 // 1-启用, 0-禁用
 	Articles  []Article `gorm:"many2many:article_tags;" json:"articles"` // 关联文章
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// TagFollow 用户关注的标签
+type TagFollow struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_user_tag" json:"user_id"`      // 用户ID
+	TagID     uint      `gorm:"not null;uniqueIndex:idx_user_tag;index" json:"tag_id"` // 标签ID
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (TagFollow) TableName() string {
+	return "tag_follows"
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// Upload 上传文件记录，用于个人数据导出和账号注销时清理文件
+type Upload struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;index" json:"user_id"` // 上传用户ID
+	Path         string    `gorm:"size:255;not null" json:"path"` // 相对于上传目录的文件路径
+	OriginalName string    `gorm:"size:255" json:"original_name"` // 原始文件名
+	ContentType  string    `gorm:"size:100" json:"content_type"`  // 文件类型
+	Size         int64     `json:"size"`                          // 文件大小（字节）
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Upload) TableName() string {
+	return "uploads"
+}
@@ -4,18 +4,33 @@ import (
 	"time"
 )
 
+// 用户状态
+const (
+	UserStatusDisabled = 0 // 禁用
+	UserStatusActive   = 1 // 正常
+	UserStatusPending  = 2 // 等待管理员审核
+)
+
 // User 用户模型
 type User struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Username  string    `gorm:"unique;not null" json:"username"`
-	Nickname  string    `json:"nickname"`
-	Email     string    `gorm:"unique" json:"email"`
-	Password  string    `json:"password"`
-	Avatar    string    `json:"avatar"`
-	Status    int       `gorm:"default:1" json:"status"`           // 1-正常, 0-禁用
-	Articles  []Article `gorm:"foreignKey:UserID" json:"articles"` // 关联文章
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	Username         string    `gorm:"unique;not null" json:"username"`
+	Nickname         string    `json:"nickname"`
+	Email            string    `gorm:"unique" json:"email"`
+	EmailVerified    bool      `gorm:"default:false" json:"email_verified"` // 邮箱是否已验证
+	Password         string    `json:"password"`
+	Avatar           string    `json:"avatar"`
+	Bio              string    `gorm:"size:500" json:"bio"`                     // 个人简介
+	TwoFactorEnabled bool      `gorm:"default:false" json:"two_factor_enabled"` // 是否启用两步验证
+	TOTPSecret       string    `gorm:"size:255" json:"-"`                       // 加密存储的TOTP密钥
+	TOTPLastStep     int64     `gorm:"default:0" json:"-"`                      // 最近一次使用的TOTP时间步，防止验证码重放
+	RecoveryCodes    string    `gorm:"type:text" json:"-"`                      // 恢复码哈希列表（JSON）
+	Role             string    `gorm:"size:20;default:author" json:"role"`      // 角色：admin/editor/author/reader
+	Status           int       `gorm:"default:1" json:"status"`                 // 1-正常, 0-禁用, 2-待审核
+	IsSystem         bool      `gorm:"default:false" json:"-"`                  // 系统账号（注销用户的匿名账号），由程序创建，不能登录或注销
+	Articles         []Article `gorm:"foreignKey:UserID" json:"articles"`       // 关联文章
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 // TableName 指定表名
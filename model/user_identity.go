@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// UserIdentity 外部身份模型，将第三方身份提供方的账号关联到本地用户
+type UserIdentity struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`                                     // 本地用户ID
+	Provider  string    `gorm:"size:50;not null;uniqueIndex:idx_provider_subject" json:"provider"` // 身份提供方名称
+	Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_provider_subject" json:"subject"` // 身份提供方中的用户标识（sub）
+	Email     string    `gorm:"size:255" json:"email"`                                             // 身份提供方返回的邮箱
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+)
+
+// 一次性令牌用途
+const (
+	TokenPurposePasswordReset = "password_reset" // 重置密码
+	TokenPurposeEmailVerify   = "email_verify"   // 验证邮箱
+	TokenPurposeTwoFactor     = "two_factor"     // 两步验证登录挑战
+)
+
+// UserToken 用户一次性令牌模型（重置密码、验证邮箱、两步验证挑战等），只保存令牌哈希
+type UserToken struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`         // 所属用户ID
+	Purpose   string     `gorm:"size:30;not null;index" json:"purpose"` // 令牌用途
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex" json:"-"` // 令牌的SHA-256哈希
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`            // 过期时间
+	Attempts  int        `gorm:"default:0" json:"attempts"`             // 校验失败次数
+	UsedAt    *time.Time `json:"used_at,omitempty"`                     // 使用时间，使用后即失效
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
@@ -0,0 +1,408 @@
+package router
+
+import (
+	"errors"
+	"gin-blog-system/middleware"
+	"gin-blog-system/model"
+	"gin-blog-system/service"
+	"gin-blog-system/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RegisterAdminRoutes 注册管理后台相关路由
+func RegisterAdminRoutes(rg *gin.RouterGroup) {
+	admin := rg.Group("/admin", middleware.AuthMiddleware(), middleware.RequirePermission(model.PermUserManage))
+	{
+		// 修改用户角色
+		admin.PUT("/users/:id/role", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的用户ID")
+				return
+			}
+
+			var req struct {
+				Role string `json:"role" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			if err := service.UpdateUserRole(uint(id), req.Role, auditContext(c)); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, map[string]interface{}{
+				"user_id": id,
+				"role":    req.Role,
+			})
+		})
+
+		// 修改用户状态（禁用用户会立即作废其全部令牌）
+		admin.PUT("/users/:id/status", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的用户ID")
+				return
+			}
+
+			var req struct {
+				Status *int `json:"status" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			if err := service.UpdateUserStatus(uint(id), *req.Status, auditContext(c)); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, map[string]interface{}{
+				"user_id": id,
+				"status":  *req.Status,
+			})
+		})
+
+		// 注销用户并删除其个人数据（后台执行，返回任务ID用于查询进度）
+		admin.DELETE("/users/:id", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的用户ID")
+				return
+			}
+
+			job, err := service.RequestUserErasure(uint(id), c.GetUint("user_id"))
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Result(c, http.StatusAccepted, job, "注销任务已创建")
+		})
+
+		// 解除用户的登录锁定
+		admin.DELETE("/users/:id/lockout", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的用户ID")
+				return
+			}
+
+			if err := service.UnlockAccount(uint(id)); err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			utils.Success(c, "已解除登录锁定")
+		})
+
+		// 获取指定用户的登录会话列表
+		admin.GET("/users/:id/sessions", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的用户ID")
+				return
+			}
+
+			sessions, err := service.ListUserSessions(uint(id), c.GetUint("session_id"))
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取会话列表失败")
+				return
+			}
+			utils.Success(c, sessions)
+		})
+
+		// 注销指定用户的某个会话
+		admin.DELETE("/users/:id/sessions/:session_id", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的用户ID")
+				return
+			}
+
+			sessionIDParam := c.Param("session_id")
+			sessionID, err := strconv.ParseUint(sessionIDParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的会话ID")
+				return
+			}
+
+			if err := service.RevokeSession(uint(id), uint(sessionID)); err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			utils.Success(c, "会话已注销")
+		})
+
+		// 注销指定用户的全部会话
+		admin.DELETE("/users/:id/sessions", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的用户ID")
+				return
+			}
+
+			if err := service.RevokeAllSessions(uint(id)); err != nil {
+				utils.Error(c, http.StatusInternalServerError, "注销会话失败: "+err.Error())
+				return
+			}
+
+			utils.Success(c, "已注销该用户的全部会话")
+		})
+
+		// 模拟指定用户登录，返回带有 act 声明的短期访问令牌
+		admin.POST("/users/:id/impersonate", middleware.DenyImpersonation(), func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的用户ID")
+				return
+			}
+
+			token, err := service.IssueImpersonationToken(c.GetUint("user_id"), uint(id), c.ClientIP())
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, token)
+		})
+
+		// 作废模拟令牌
+		admin.DELETE("/impersonation/:jti", middleware.DenyImpersonation(), func(c *gin.Context) {
+			if err := service.RevokeImpersonationToken(c.Param("jti"), c.GetUint("user_id"), c.ClientIP()); err != nil {
+				if errors.Is(err, service.ErrImpersonationNotFound) {
+					utils.Error(c, http.StatusNotFound, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusInternalServerError, "作废模拟令牌失败: "+err.Error())
+				return
+			}
+
+			utils.Success(c, "已作废模拟令牌")
+		})
+
+		// 查询模拟登录审计记录
+		admin.GET("/impersonation-events", func(c *gin.Context) {
+			pageStr := c.DefaultQuery("page", "1")
+			pageSizeStr := c.DefaultQuery("page_size", "20")
+
+			page, _ := strconv.Atoi(pageStr)
+			pageSize, _ := strconv.Atoi(pageSizeStr)
+
+			if page < 1 {
+				page = 1
+			}
+			if pageSize < 1 || pageSize > 100 {
+				pageSize = 20
+			}
+
+			adminID, _ := strconv.ParseUint(c.Query("admin_id"), 10, 32)
+			userID, _ := strconv.ParseUint(c.Query("user_id"), 10, 32)
+
+			events, total, err := service.ListImpersonationEvents(service.ImpersonationEventFilter{
+				AdminID: uint(adminID),
+				UserID:  uint(userID),
+			}, page, pageSize)
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取审计记录失败: "+err.Error())
+				return
+			}
+
+			response := map[string]interface{}{
+				"events":    events,
+				"total":     total,
+				"page":      page,
+				"page_size": pageSize,
+			}
+			utils.Success(c, response)
+		})
+
+		// 查询审计日志，支持按操作者、操作、对象、请求ID和时间范围过滤
+		admin.GET("/audit", func(c *gin.Context) {
+			pageStr := c.DefaultQuery("page", "1")
+			pageSizeStr := c.DefaultQuery("page_size", "20")
+
+			page, _ := strconv.Atoi(pageStr)
+			pageSize, _ := strconv.Atoi(pageSizeStr)
+
+			if page < 1 {
+				page = 1
+			}
+			if pageSize < 1 || pageSize > 100 {
+				pageSize = 20
+			}
+
+			actorID, _ := strconv.ParseUint(c.Query("actor_id"), 10, 32)
+			targetID, _ := strconv.ParseUint(c.Query("target_id"), 10, 32)
+
+			filter := service.AuditEventFilter{
+				ActorID:    uint(actorID),
+				Action:     c.Query("action"),
+				TargetType: c.Query("target_type"),
+				TargetID:   uint(targetID),
+				RequestID:  c.Query("request_id"),
+			}
+			var err error
+			if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			events, total, err := service.ListAuditEvents(filter, page, pageSize)
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取审计日志失败: "+err.Error())
+				return
+			}
+
+			response := map[string]interface{}{
+				"events":    events,
+				"total":     total,
+				"page":      page,
+				"page_size": pageSize,
+			}
+			utils.Success(c, response)
+		})
+
+		// 获取等待审核的用户
+		admin.GET("/users/pending", func(c *gin.Context) {
+			users, err := service.ListPendingUsers()
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取待审核用户失败: "+err.Error())
+				return
+			}
+			utils.Success(c, users)
+		})
+
+		// 审核通过用户
+		admin.POST("/users/:id/approve", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的用户ID")
+				return
+			}
+
+			if err := service.ApproveUser(uint(id), auditContext(c)); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, "已审核通过")
+		})
+
+		// 拒绝用户注册（删除该账号）
+		admin.POST("/users/:id/reject", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的用户ID")
+				return
+			}
+
+			if err := service.RejectUser(uint(id), auditContext(c)); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, "已拒绝该用户的注册申请")
+		})
+
+		// 获取邀请码列表
+		admin.GET("/invites", func(c *gin.Context) {
+			invites, err := service.ListInviteCodes()
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取邀请码列表失败: "+err.Error())
+				return
+			}
+			utils.Success(c, invites)
+		})
+
+		// 创建邀请码
+		admin.POST("/invites", func(c *gin.Context) {
+			var req struct {
+				MaxUses       int    `json:"max_uses"`        // 最大使用次数，默认1次
+				ExpiresInDays int    `json:"expires_in_days"` // 有效天数，0表示永不过期
+				Note          string `json:"note"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+			if req.MaxUses == 0 {
+				req.MaxUses = 1
+			}
+			if req.ExpiresInDays < 0 {
+				utils.Error(c, http.StatusBadRequest, "有效天数不能为负数")
+				return
+			}
+
+			var expiresAt *time.Time
+			if req.ExpiresInDays > 0 {
+				t := time.Now().AddDate(0, 0, req.ExpiresInDays)
+				expiresAt = &t
+			}
+
+			invite, err := service.CreateInviteCode(c.GetUint("user_id"), req.MaxUses, expiresAt, req.Note)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			// 邀请码明文只在创建时返回一次
+			utils.Success(c, invite)
+		})
+
+		// 作废邀请码
+		admin.DELETE("/invites/:id", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的邀请码ID")
+				return
+			}
+
+			if err := service.RevokeInviteCode(uint(id)); err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			utils.Success(c, "邀请码已作废")
+		})
+	}
+}
+
+// parseTimeQuery 解析时间查询参数，支持 RFC3339 和 2006-01-02 两种格式，参数为空时返回 nil
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, errors.New("无效的时间参数: " + key)
+	}
+	return &t, nil
+}
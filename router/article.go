@@ -1,6 +1,7 @@
 package router
 
 import (
+	"errors"
 	"gin-blog-system/config"
 	"gin-blog-system/middleware"
 	"gin-blog-system/model"
@@ -9,42 +10,49 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 // RegisterArticleRoutes 注册文章相关路由
 func RegisterArticleRoutes(rg *gin.RouterGroup) {
-	article := rg.Group("/articles", middleware.AuthMiddleware())
+	article := rg.Group("/articles", middleware.AuthMiddleware(model.ScopeArticlesRead, model.ScopeArticlesWrite))
 	{
-		// 获取文章列表
+		// 获取文章列表，支持按状态、分类、标签、作者和时间过滤，自定义排序和返回字段
 		article.GET("", func(c *gin.Context) {
-			pageStr := c.DefaultQuery("page", "1")
-			pageSizeStr := c.DefaultQuery("page_size", "10")
-
-			page, _ := strconv.Atoi(pageStr)
-			pageSize, _ := strconv.Atoi(pageSizeStr)
-
-			if page < 1 {
-				page = 1
+			req, err := parsePageRequest(c, 10)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
 			}
-			if pageSize < 1 || pageSize > 100 {
-				pageSize = 10
+
+			filter, err := service.ParseArticleListFilter(c.Request.URL.Query())
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
 			}
 
-			articles, total, err := service.GetAllArticles(page, pageSize)
+			articles, page, err := service.GetAllArticles(filter, req, c.GetUint("user_id"), c.GetString("role"))
 			if err != nil {
+				if errors.Is(err, service.ErrInvalidCursor) {
+					utils.Error(c, http.StatusBadRequest, err.Error())
+					return
+				}
 				utils.Error(c, http.StatusInternalServerError, "获取文章列表失败")
 				return
 			}
 
-			response := map[string]interface{}{
-				"articles":  articles,
-				"total":     total,
-				"page":      page,
-				"page_size": pageSize,
+			// 指定了 fields 时只返回这些字段
+			var items interface{} = articles
+			if len(filter.Fields) > 0 {
+				if items, err = service.SelectArticleFields(articles, filter.Fields); err != nil {
+					utils.Error(c, http.StatusInternalServerError, "获取文章列表失败")
+					return
+				}
 			}
-			utils.Success(c, response)
+
+			utils.Success(c, pageResponse("articles", items, req, page))
 		})
 
 		// 根据ID获取单篇文章
@@ -56,6 +64,12 @@ func RegisterArticleRoutes(rg *gin.RouterGroup) {
 				return
 			}
 
+			// 未发布的文章只对作者和审核人可见
+			if err := service.CheckArticleVisible(uint(id), c.GetUint("user_id"), c.GetString("role")); err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
 			// 先增加浏览量
 			updateResult := config.DB.Model(&model.Article{}).Where("id = ?", id).UpdateColumn("view_count", gorm.Expr("view_count + ?", 1))
 			if updateResult.Error != nil {
@@ -72,19 +86,55 @@ func RegisterArticleRoutes(rg *gin.RouterGroup) {
 			utils.Success(c, article)
 		})
 
+		// 根据 slug 获取单篇文章，旧 slug 永久重定向到当前地址
+		article.GET("/slug/:slug", func(c *gin.Context) {
+			id, slug, err := service.ResolveSlug(service.SlugTargetArticle, c.Param("slug"))
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			if slug != c.Param("slug") {
+				redirectToSlug(c, slug)
+				return
+			}
+
+			// 未发布的文章只对作者和审核人可见
+			if err := service.CheckArticleVisible(id, c.GetUint("user_id"), c.GetString("role")); err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			// 先增加浏览量
+			updateResult := config.DB.Model(&model.Article{}).Where("id = ?", id).UpdateColumn("view_count", gorm.Expr("view_count + ?", 1))
+			if updateResult.Error != nil {
+				utils.Error(c, http.StatusInternalServerError, "更新浏览量失败: "+updateResult.Error.Error())
+				return
+			}
+
+			article, err := service.GetArticleByID(id)
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			utils.Success(c, article)
+		})
+
 		// 临时结构体用于接收包含TagIDs的请求
 		type ArticleRequest struct {
-			Title      string `json:"title"`
-			Content    string `json:"content"`
-			Summary    string `json:"summary"`
-			Cover      string `json:"cover"`
-			Status     int    `json:"status"`
-			CategoryID uint   `json:"category_id"`
-			TagIDs     []uint `json:"tag_ids,omitempty"`
+			Title         string     `json:"title"`
+			Slug          string     `json:"slug"` // 可选，默认根据标题生成
+			Content       string     `json:"content"`
+			ContentFormat string     `json:"content_format"` // 可选，markdown（默认）/html/plain
+			Summary       string     `json:"summary"`
+			Cover         string     `json:"cover"`
+			UnpublishAt   *time.Time `json:"unpublish_at"` // 可选，到达后文章归档（RFC3339）
+			CategoryID    uint       `json:"category_id"`
+			TagIDs        []uint     `json:"tag_ids,omitempty"`
 		}
 
 		// 创建文章
-		article.POST("", func(c *gin.Context) {
+		article.POST("", middleware.RequireScope(model.ScopeArticlesWrite), middleware.RequirePermission(model.PermArticleCreate), func(c *gin.Context) {
 			var req ArticleRequest
 			if err := c.ShouldBindJSON(&req); err != nil {
 				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
@@ -100,13 +150,15 @@ func RegisterArticleRoutes(rg *gin.RouterGroup) {
 
 			// 构建文章模型
 			article := model.Article{
-				Title:      req.Title,
-				Content:    req.Content,
-				Summary:    req.Summary,
-				Cover:      req.Cover,
-				Status:     req.Status,
-				UserID:     userID.(uint),
-				CategoryID: req.CategoryID,
+				Title:         req.Title,
+				Slug:          req.Slug,
+				Content:       req.Content,
+				ContentFormat: req.ContentFormat,
+				Summary:       req.Summary,
+				Cover:         req.Cover,
+				UnpublishAt:   req.UnpublishAt,
+				UserID:        userID.(uint),
+				CategoryID:    req.CategoryID,
 			}
 
 			// 如果提供了TagIDs，则加载对应的标签
@@ -120,7 +172,11 @@ func RegisterArticleRoutes(rg *gin.RouterGroup) {
 				article.Tags = tags
 			}
 
-			if err := service.CreateArticle(&article); err != nil {
+			if err := service.CreateArticle(&article, auditContext(c)); err != nil {
+				if isArticleInputError(err) {
+					utils.Error(c, http.StatusBadRequest, err.Error())
+					return
+				}
 				utils.Error(c, http.StatusInternalServerError, "创建文章失败: "+err.Error())
 				return
 			}
@@ -135,7 +191,7 @@ func RegisterArticleRoutes(rg *gin.RouterGroup) {
 		})
 
 		// 更新文章
-		article.PUT("/:id", func(c *gin.Context) {
+		article.PUT("/:id", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
 			idParam := c.Param("id")
 			id, err := strconv.ParseUint(idParam, 10, 32)
 			if err != nil {
@@ -156,15 +212,26 @@ func RegisterArticleRoutes(rg *gin.RouterGroup) {
 				return
 			}
 
-			// 构建文章模型
+			// 检查是否有权编辑该文章
+			if err := service.CheckArticlePermission(uint(id), userID.(uint), c.GetString("role")); err != nil {
+				if errors.Is(err, service.ErrPermissionDenied) {
+					utils.Error(c, http.StatusForbidden, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			// 构建文章模型（不修改作者）
 			articleData := model.Article{
-				Title:      req.Title,
-				Content:    req.Content,
-				Summary:    req.Summary,
-				Cover:      req.Cover,
-				Status:     req.Status,
-				UserID:     userID.(uint),
-				CategoryID: req.CategoryID,
+				Title:         req.Title,
+				Slug:          req.Slug,
+				Content:       req.Content,
+				ContentFormat: req.ContentFormat,
+				Summary:       req.Summary,
+				Cover:         req.Cover,
+				UnpublishAt:   req.UnpublishAt,
+				CategoryID:    req.CategoryID,
 			}
 
 			// 如果提供了TagIDs，则加载对应的标签
@@ -178,7 +245,15 @@ func RegisterArticleRoutes(rg *gin.RouterGroup) {
 				articleData.Tags = tags
 			}
 
-			if err := service.UpdateArticle(uint(id), &articleData); err != nil {
+			if err := service.UpdateArticle(uint(id), &articleData, c.GetString("role"), auditContext(c)); err != nil {
+				if errors.Is(err, service.ErrInvalidTransition) {
+					utils.Error(c, http.StatusConflict, err.Error())
+					return
+				}
+				if isArticleInputError(err) {
+					utils.Error(c, http.StatusBadRequest, err.Error())
+					return
+				}
 				utils.Error(c, http.StatusInternalServerError, "更新文章失败: "+err.Error())
 				return
 			}
@@ -193,7 +268,7 @@ func RegisterArticleRoutes(rg *gin.RouterGroup) {
 		})
 
 		// 删除文章
-		article.DELETE("/:id", func(c *gin.Context) {
+		article.DELETE("/:id", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
 			idParam := c.Param("id")
 			id, err := strconv.ParseUint(idParam, 10, 32)
 			if err != nil {
@@ -201,7 +276,24 @@ func RegisterArticleRoutes(rg *gin.RouterGroup) {
 				return
 			}
 
-			if err := service.DeleteArticle(uint(id)); err != nil {
+			// 获取用户ID（从中间件设置的上下文）
+			userID, exists := c.Get("user_id")
+			if !exists {
+				utils.Error(c, http.StatusUnauthorized, "请先登录")
+				return
+			}
+
+			// 检查是否有权删除该文章（只有主作者本人，合著者不能删除）
+			if err := service.CheckArticleOwnerPermission(uint(id), userID.(uint), c.GetString("role")); err != nil {
+				if errors.Is(err, service.ErrPermissionDenied) {
+					utils.Error(c, http.StatusForbidden, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			if err := service.DeleteArticle(uint(id), auditContext(c)); err != nil {
 				utils.Error(c, http.StatusInternalServerError, "删除文章失败: "+err.Error())
 				return
 			}
@@ -209,8 +301,75 @@ func RegisterArticleRoutes(rg *gin.RouterGroup) {
 			utils.Success(c, nil)
 		})
 
+		// 获取文章的署名作者
+		article.GET("/:id/authors", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的文章ID")
+				return
+			}
+
+			if err := service.CheckArticleVisible(uint(id), c.GetUint("user_id"), c.GetString("role")); err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			authors, err := service.GetArticleAuthors(uint(id))
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			utils.Success(c, authors)
+		})
+
+		// 按顺序替换文章的署名作者（主作者本人、编辑或管理员）
+		article.PUT("/:id/authors", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的文章ID")
+				return
+			}
+
+			if err := service.CheckArticleOwnerPermission(uint(id), c.GetUint("user_id"), c.GetString("role")); err != nil {
+				if errors.Is(err, service.ErrPermissionDenied) {
+					utils.Error(c, http.StatusForbidden, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			var req struct {
+				Authors []service.ArticleAuthorInput `json:"authors" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			if err := service.SetArticleAuthors(uint(id), req.Authors, auditContext(c)); err != nil {
+				if errors.Is(err, service.ErrInvalidArticleAuthors) {
+					utils.Error(c, http.StatusBadRequest, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusInternalServerError, "更新署名失败: "+err.Error())
+				return
+			}
+
+			authors, err := service.GetArticleAuthors(uint(id))
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取署名失败")
+				return
+			}
+
+			utils.Success(c, authors)
+		})
+
 		// 点赞文章
-		article.POST("/:id/like", func(c *gin.Context) {
+		article.POST("/:id/like", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
 			idParam := c.Param("id")
 			id, err := strconv.ParseUint(idParam, 10, 32)
 			if err != nil {
@@ -253,7 +412,7 @@ func RegisterArticleRoutes(rg *gin.RouterGroup) {
 		})
 
 		// 取消点赞
-		article.DELETE("/:id/like", func(c *gin.Context) {
+		article.DELETE("/:id/like", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
 			idParam := c.Param("id")
 			id, err := strconv.ParseUint(idParam, 10, 32)
 			if err != nil {
@@ -320,5 +479,154 @@ func RegisterArticleRoutes(rg *gin.RouterGroup) {
 
 			utils.Success(c, map[string]bool{"is_liked": userLike})
 		})
+
+		// 获取文章的修订列表（作者本人、编辑或管理员）
+		article.GET("/:id/revisions", func(c *gin.Context) {
+			id, ok := requireArticleEditor(c)
+			if !ok {
+				return
+			}
+
+			pageStr := c.DefaultQuery("page", "1")
+			pageSizeStr := c.DefaultQuery("page_size", "20")
+
+			page, _ := strconv.Atoi(pageStr)
+			pageSize, _ := strconv.Atoi(pageSizeStr)
+
+			if page < 1 {
+				page = 1
+			}
+			if pageSize < 1 || pageSize > 100 {
+				pageSize = 20
+			}
+
+			revisions, total, err := service.ListArticleRevisions(id, page, pageSize)
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取修订列表失败")
+				return
+			}
+
+			response := map[string]interface{}{
+				"revisions": revisions,
+				"total":     total,
+				"page":      page,
+				"page_size": pageSize,
+			}
+			utils.Success(c, response)
+		})
+
+		// 比较两个修订：from 默认为 to 的上一个修订，to 默认为最新修订；mode 为 unified（默认）或 word
+		article.GET("/:id/revisions/diff", func(c *gin.Context) {
+			id, ok := requireArticleEditor(c)
+			if !ok {
+				return
+			}
+
+			to, err := strconv.Atoi(c.DefaultQuery("to", "0"))
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的修订号")
+				return
+			}
+			if to == 0 {
+				if to, err = service.GetLatestRevisionNumber(id); err != nil {
+					utils.Error(c, http.StatusInternalServerError, "获取修订失败")
+					return
+				}
+			}
+			from, err := strconv.Atoi(c.DefaultQuery("from", strconv.Itoa(to-1)))
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的修订号")
+				return
+			}
+
+			mode := c.DefaultQuery("mode", service.RevisionDiffUnified)
+			if mode != service.RevisionDiffUnified && mode != service.RevisionDiffWord {
+				utils.Error(c, http.StatusBadRequest, "无效的差异模式")
+				return
+			}
+
+			diff, err := service.DiffArticleRevisions(id, from, to, mode)
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			utils.Success(c, diff)
+		})
+
+		// 获取指定修订的完整内容
+		article.GET("/:id/revisions/:rev", func(c *gin.Context) {
+			id, ok := requireArticleEditor(c)
+			if !ok {
+				return
+			}
+
+			rev, err := strconv.Atoi(c.Param("rev"))
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的修订号")
+				return
+			}
+
+			revision, err := service.GetArticleRevision(id, rev)
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			utils.Success(c, revision.ConvertToArticleRevisionResponse(true))
+		})
+
+		// 将文章恢复为指定修订
+		article.POST("/:id/revisions/:rev/restore", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
+			id, ok := requireArticleEditor(c)
+			if !ok {
+				return
+			}
+
+			rev, err := strconv.Atoi(c.Param("rev"))
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的修订号")
+				return
+			}
+
+			if err := service.RestoreArticleRevision(id, rev, c.GetString("role"), auditContext(c)); err != nil {
+				if errors.Is(err, service.ErrInvalidTransition) {
+					utils.Error(c, http.StatusConflict, "恢复修订失败: "+err.Error())
+					return
+				}
+				utils.Error(c, http.StatusBadRequest, "恢复修订失败: "+err.Error())
+				return
+			}
+
+			restoredArticle, err := service.GetArticleByID(id)
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取恢复后的文章失败")
+				return
+			}
+			utils.Success(c, restoredArticle)
+		})
 	}
 }
+
+// requireArticleEditor 解析路径中的文章ID并检查当前用户是否有权编辑该文章，无权时直接写入错误响应
+func requireArticleEditor(c *gin.Context) (uint, bool) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "无效的文章ID")
+		return 0, false
+	}
+
+	if err := service.CheckArticlePermission(uint(id), c.GetUint("user_id"), c.GetString("role")); err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Error(c, http.StatusForbidden, err.Error())
+			return 0, false
+		}
+		utils.Error(c, http.StatusNotFound, err.Error())
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// isArticleInputError 判断文章创建/更新失败是否由请求参数错误导致
+func isArticleInputError(err error) bool {
+	return errors.Is(err, service.ErrInvalidUnpublishAt) || errors.Is(err, service.ErrInvalidContentFormat)
+}
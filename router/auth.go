@@ -1,11 +1,16 @@
 package router
 
 import (
+	"errors"
+	"fmt"
+	"gin-blog-system/middleware"
 	"gin-blog-system/model"
 	"gin-blog-system/service"
 	"gin-blog-system/utils"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // RegisterAuthRoutes 注册认证相关路由
@@ -36,46 +41,380 @@ func RegisterAuthRoutes(rg *gin.RouterGroup) {
 				return
 			}
 
-			user, err := service.AuthenticateUser(loginInfo.Username, loginInfo.Password)
+			user, err := service.AuthenticateUser(loginInfo.Username, loginInfo.Password, c.Request.UserAgent(), auditContext(c))
 			if err != nil {
+				var lockedErr *service.LoginLockedError
+				if errors.As(err, &lockedErr) {
+					utils.Result(c, http.StatusTooManyRequests, map[string]interface{}{
+						"unlock_at": utils.CustomTime{Time: lockedErr.UnlockAt},
+					}, lockedErr.Error())
+					return
+				}
+				if errors.Is(err, service.ErrPendingApproval) {
+					utils.Error(c, http.StatusForbidden, err.Error())
+					return
+				}
 				utils.Error(c, http.StatusUnauthorized, err.Error())
 				return
 			}
 
-			token, err := service.GenerateToken(user)
+			// 启用两步验证的用户需要先通过 /2fa/verify 兑换挑战令牌
+			if user.TwoFactorEnabled {
+				challengeToken, expiresIn, err := service.CreateTwoFactorChallenge(user.ID)
+				if err != nil {
+					utils.Error(c, http.StatusInternalServerError, "创建两步验证挑战失败: "+err.Error())
+					return
+				}
+				utils.Success(c, map[string]interface{}{
+					"two_factor_required": true,
+					"challenge_token":     challengeToken,
+					"expires_in":          expiresIn,
+				})
+				return
+			}
+
+			tokens, err := service.IssueTokenPair(user, c.ClientIP(), c.Request.UserAgent())
 			if err != nil {
 				utils.Error(c, http.StatusInternalServerError, "生成令牌失败: "+err.Error())
 				return
 			}
 
 			response := map[string]interface{}{
-				"user":  user,
-				"token": token,
+				"user":          user.ConvertToUserResponse(),
+				"token":         tokens.AccessToken,
+				"refresh_token": tokens.RefreshToken,
+				"expires_in":    tokens.ExpiresIn,
 			}
 			utils.Success(c, response)
 		})
 
+		// 使用两步验证码兑换登录挑战令牌
+		auth.POST("/2fa/verify", func(c *gin.Context) {
+			var req struct {
+				ChallengeToken string `json:"challenge_token" binding:"required"`
+				Code           string `json:"code" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			user, err := service.VerifyTwoFactorChallenge(req.ChallengeToken, req.Code, c.Request.UserAgent(), auditContext(c))
+			if err != nil {
+				var lockedErr *service.LoginLockedError
+				if errors.As(err, &lockedErr) {
+					utils.Result(c, http.StatusTooManyRequests, map[string]interface{}{
+						"unlock_at": utils.CustomTime{Time: lockedErr.UnlockAt},
+					}, lockedErr.Error())
+					return
+				}
+				utils.Error(c, http.StatusUnauthorized, err.Error())
+				return
+			}
+
+			tokens, err := service.IssueTokenPair(user, c.ClientIP(), c.Request.UserAgent())
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "生成令牌失败: "+err.Error())
+				return
+			}
+
+			response := map[string]interface{}{
+				"user":          user.ConvertToUserResponse(),
+				"token":         tokens.AccessToken,
+				"refresh_token": tokens.RefreshToken,
+				"expires_in":    tokens.ExpiresIn,
+			}
+			utils.Success(c, response)
+		})
+
+		// 获取两步验证密钥和 otpauth URI
+		auth.POST("/2fa/setup", middleware.AuthMiddleware(), middleware.DenyImpersonation(), func(c *gin.Context) {
+			setup, err := service.SetupTwoFactor(c.GetUint("user_id"))
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			utils.Success(c, setup)
+		})
+
+		// 校验验证码并启用两步验证
+		auth.POST("/2fa/confirm", middleware.AuthMiddleware(), middleware.DenyImpersonation(), func(c *gin.Context) {
+			var req struct {
+				Code string `json:"code" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			recoveryCodes, err := service.ConfirmTwoFactor(c.GetUint("user_id"), req.Code)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			// 恢复码只在此时返回一次，请妥善保存
+			utils.Success(c, map[string]interface{}{
+				"recovery_codes": recoveryCodes,
+			})
+		})
+
+		// 关闭两步验证
+		auth.POST("/2fa/disable", middleware.AuthMiddleware(), middleware.DenyImpersonation(), func(c *gin.Context) {
+			var req struct {
+				Password string `json:"password" binding:"required"`
+				Code     string `json:"code" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			if err := service.DisableTwoFactor(c.GetUint("user_id"), req.Password, req.Code); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, "两步验证已关闭")
+		})
+
+		// 使用刷新令牌换取新的令牌对
+		auth.POST("/refresh", func(c *gin.Context) {
+			var req struct {
+				RefreshToken string `json:"refresh_token" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			tokens, err := service.RefreshTokens(req.RefreshToken, c.ClientIP())
+			if err != nil {
+				utils.Error(c, http.StatusUnauthorized, err.Error())
+				return
+			}
+
+			utils.Success(c, tokens)
+		})
+
+		// 获取当前注册模式，前端据此决定是否显示邀请码输入框
+		auth.GET("/registration", func(c *gin.Context) {
+			utils.Success(c, map[string]interface{}{
+				"mode": service.RegistrationMode(),
+			})
+		})
+
 		auth.POST("/register", func(c *gin.Context) {
-			var user model.User
-			if err := c.ShouldBindJSON(&user); err != nil {
+			var req struct {
+				Username   string `json:"username" binding:"required"`
+				Password   string `json:"password" binding:"required"`
+				Email      string `json:"email" binding:"required"`
+				Nickname   string `json:"nickname"`
+				InviteCode string `json:"invite_code"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
 				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
 				return
 			}
 
-			if err := service.CreateUser(&user); err != nil {
-				utils.Error(c, http.StatusInternalServerError, err.Error())
+			user, err := service.RegisterUser(service.RegisterInput{
+				Username:   req.Username,
+				Password:   req.Password,
+				Email:      req.Email,
+				Nickname:   req.Nickname,
+				InviteCode: req.InviteCode,
+			}, auditContext(c))
+			if err != nil {
+				switch {
+				case errors.Is(err, service.ErrRegistrationClosed),
+					errors.Is(err, service.ErrInviteCodeRequired),
+					errors.Is(err, service.ErrInvalidInviteCode):
+					utils.Error(c, http.StatusForbidden, err.Error())
+				default:
+					utils.Error(c, http.StatusBadRequest, err.Error())
+				}
 				return
 			}
 
-			// 不返回密码
-			user.Password = ""
-			utils.Success(c, user)
+			// 发送邮箱验证邮件，发送失败不影响注册结果
+			if err := service.SendEmailVerification(user.ID); err != nil {
+				fmt.Printf("发送邮箱验证邮件失败: %v\n", err)
+			}
+
+			if user.Status == model.UserStatusPending {
+				utils.Result(c, http.StatusOK, user.ConvertToUserResponse(), "注册成功，请等待管理员审核")
+				return
+			}
+			utils.Success(c, user.ConvertToUserResponse())
 		})
 
-		auth.POST("/logout", func(c *gin.Context) {
-			// JWT是无状态的，服务器端不需要特殊处理登出
-			// 前端只需要删除本地存储的token即可
+		// 申请重置密码
+		auth.POST("/password/forgot", func(c *gin.Context) {
+			var req struct {
+				Email string `json:"email" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			if err := service.RequestPasswordReset(req.Email); err != nil {
+				utils.Error(c, http.StatusInternalServerError, "申请重置密码失败")
+				return
+			}
+
+			utils.Success(c, "如果该邮箱已注册，重置密码邮件已发送")
+		})
+
+		// 使用重置令牌设置新密码
+		auth.POST("/password/reset", func(c *gin.Context) {
+			var req struct {
+				Token    string `json:"token" binding:"required"`
+				Password string `json:"password" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			if err := service.ResetPassword(req.Token, req.Password); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, "密码已重置，请重新登录")
+		})
+
+		// 验证邮箱
+		auth.POST("/email/verify", func(c *gin.Context) {
+			var req struct {
+				Token string `json:"token" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			if err := service.VerifyEmail(req.Token); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, "邮箱验证成功")
+		})
+
+		// 重新发送邮箱验证邮件
+		auth.POST("/email/verify/resend", middleware.AuthMiddleware(), func(c *gin.Context) {
+			if err := service.SendEmailVerification(c.GetUint("user_id")); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, "验证邮件已发送")
+		})
+
+		auth.POST("/logout", middleware.AuthMiddleware(), func(c *gin.Context) {
+			// 作废当前访问令牌及其刷新令牌族
+			if err := service.Logout(c.GetString("jti"), c.GetUint("user_id"), c.GetTime("token_expires_at")); err != nil {
+				utils.Error(c, http.StatusInternalServerError, "登出失败: "+err.Error())
+				return
+			}
 			utils.Success(c, "登出成功")
 		})
+
+		// 获取当前用户的登录会话列表
+		auth.GET("/sessions", middleware.AuthMiddleware(), func(c *gin.Context) {
+			sessions, err := service.ListUserSessions(c.GetUint("user_id"), c.GetUint("session_id"))
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取会话列表失败")
+				return
+			}
+			utils.Success(c, sessions)
+		})
+
+		// 注销指定会话
+		auth.DELETE("/sessions/:id", middleware.AuthMiddleware(), middleware.DenyImpersonation(), func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的会话ID")
+				return
+			}
+
+			if err := service.RevokeSession(c.GetUint("user_id"), uint(id)); err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			utils.Success(c, "会话已注销")
+		})
+
+		// 获取个人访问令牌列表
+		auth.GET("/tokens", middleware.AuthMiddleware(), func(c *gin.Context) {
+			tokens, err := service.ListPersonalAccessTokens(c.GetUint("user_id"))
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取访问令牌列表失败")
+				return
+			}
+			utils.Success(c, tokens)
+		})
+
+		// 创建个人访问令牌
+		auth.POST("/tokens", middleware.AuthMiddleware(), middleware.DenyImpersonation(), func(c *gin.Context) {
+			var req struct {
+				Name          string   `json:"name" binding:"required"`
+				Scopes        []string `json:"scopes" binding:"required"`
+				ExpiresInDays int      `json:"expires_in_days"` // 有效天数，0表示永不过期
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+			if req.ExpiresInDays < 0 {
+				utils.Error(c, http.StatusBadRequest, "有效天数不能为负数")
+				return
+			}
+
+			var expiresAt *time.Time
+			if req.ExpiresInDays > 0 {
+				t := time.Now().AddDate(0, 0, req.ExpiresInDays)
+				expiresAt = &t
+			}
+
+			token, err := service.CreatePersonalAccessToken(c.GetUint("user_id"), req.Name, req.Scopes, expiresAt)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			// 令牌明文只在创建时返回一次
+			utils.Success(c, token)
+		})
+
+		// 撤销个人访问令牌
+		auth.DELETE("/tokens/:id", middleware.AuthMiddleware(), middleware.DenyImpersonation(), func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的令牌ID")
+				return
+			}
+
+			if err := service.RevokePersonalAccessToken(c.GetUint("user_id"), uint(id)); err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			utils.Success(c, "令牌已撤销")
+		})
+
+		// 在所有设备上登出
+		auth.DELETE("/sessions", middleware.AuthMiddleware(), middleware.DenyImpersonation(), func(c *gin.Context) {
+			if err := service.RevokeAllSessions(c.GetUint("user_id")); err != nil {
+				utils.Error(c, http.StatusInternalServerError, "注销会话失败: "+err.Error())
+				return
+			}
+			utils.Success(c, "已在所有设备上登出")
+		})
 	}
 }
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"errors"
+	"gin-blog-system/middleware"
 	"gin-blog-system/model"
 	"gin-blog-system/service"
 	"gin-blog-system/utils"
@@ -12,6 +14,8 @@ import (
 // RegisterCategoryRoutes 注册分类相关路由
 func RegisterCategoryRoutes(rg *gin.RouterGroup) {
 	category := rg.Group("/categories")
+	// 分类的增删改仅限拥有管理权限的角色
+	manage := category.Group("", middleware.AuthMiddleware(), middleware.RequirePermission(model.PermTaxonomyManage))
 	{
 		category.GET("", func(c *gin.Context) {
 			categories, err := service.GetAllCategories()
@@ -39,14 +43,63 @@ func RegisterCategoryRoutes(rg *gin.RouterGroup) {
 			utils.Success(c, category)
 		})
 
-		category.POST("", func(c *gin.Context) {
+		// 分页获取分类下已发布的文章，支持页码分页和游标分页
+		category.GET("/:id/articles", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的分类ID")
+				return
+			}
+
+			req, err := parsePageRequest(c, 10)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			articles, page, err := service.GetArticlesByCategory(uint(id), req)
+			if err != nil {
+				if errors.Is(err, service.ErrInvalidCursor) {
+					utils.Error(c, http.StatusBadRequest, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusInternalServerError, "获取文章列表失败")
+				return
+			}
+
+			utils.Success(c, pageResponse("articles", articles, req, page))
+		})
+
+		// 根据 slug 获取分类，旧 slug 永久重定向到当前地址
+		category.GET("/slug/:slug", func(c *gin.Context) {
+			id, slug, err := service.ResolveSlug(service.SlugTargetCategory, c.Param("slug"))
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			if slug != c.Param("slug") {
+				redirectToSlug(c, slug)
+				return
+			}
+
+			category, err := service.GetCategoryByID(id)
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			utils.Success(c, category)
+		})
+
+		manage.POST("", func(c *gin.Context) {
 			var category model.Category
 			if err := c.ShouldBindJSON(&category); err != nil {
 				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
 				return
 			}
 
-			if err := service.CreateCategory(&category); err != nil {
+			if err := service.CreateCategory(&category, auditContext(c)); err != nil {
 				utils.Error(c, http.StatusInternalServerError, "创建分类失败: "+err.Error())
 				return
 			}
@@ -54,7 +107,7 @@ func RegisterCategoryRoutes(rg *gin.RouterGroup) {
 			utils.Success(c, category)
 		})
 
-		category.PUT("/:id", func(c *gin.Context) {
+		manage.PUT("/:id", func(c *gin.Context) {
 			idParam := c.Param("id")
 			id, err := strconv.ParseUint(idParam, 10, 32)
 			if err != nil {
@@ -68,7 +121,7 @@ func RegisterCategoryRoutes(rg *gin.RouterGroup) {
 				return
 			}
 
-			if err := service.UpdateCategory(uint(id), &categoryData); err != nil {
+			if err := service.UpdateCategory(uint(id), &categoryData, auditContext(c)); err != nil {
 				utils.Error(c, http.StatusInternalServerError, "更新分类失败: "+err.Error())
 				return
 			}
@@ -82,7 +135,7 @@ func RegisterCategoryRoutes(rg *gin.RouterGroup) {
 			utils.Success(c, updatedCategory)
 		})
 
-		category.DELETE("/:id", func(c *gin.Context) {
+		manage.DELETE("/:id", func(c *gin.Context) {
 			idParam := c.Param("id")
 			id, err := strconv.ParseUint(idParam, 10, 32)
 			if err != nil {
@@ -90,7 +143,7 @@ func RegisterCategoryRoutes(rg *gin.RouterGroup) {
 				return
 			}
 
-			if err := service.DeleteCategory(uint(id)); err != nil {
+			if err := service.DeleteCategory(uint(id), auditContext(c)); err != nil {
 				utils.Error(c, http.StatusInternalServerError, "删除分类失败: "+err.Error())
 				return
 			}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"errors"
 	"gin-blog-system/middleware"
 	"gin-blog-system/model"
 	"gin-blog-system/service"
@@ -12,10 +13,10 @@ import (
 
 // RegisterCommentRoutes 注册评论相关路由
 func RegisterCommentRoutes(rg *gin.RouterGroup) {
-	comment := rg.Group("/comments", middleware.AuthMiddleware())
+	comment := rg.Group("/comments", middleware.AuthMiddleware(model.ScopeArticlesRead, model.ScopeCommentsWrite))
 	{
 		// 创建评论
-		comment.POST("", func(c *gin.Context) {
+		comment.POST("", middleware.RequireScope(model.ScopeCommentsWrite), func(c *gin.Context) {
 			var req struct {
 				Content   string `json:"content" binding:"required"`
 				ArticleID uint   `json:"article_id" binding:"required"`
@@ -41,7 +42,7 @@ func RegisterCommentRoutes(rg *gin.RouterGroup) {
 				ParentID:  req.ParentID,
 			}
 
-			if err := service.CreateComment(&comment); err != nil {
+			if err := service.CreateComment(&comment, auditContext(c)); err != nil {
 				utils.Error(c, http.StatusInternalServerError, "创建评论失败: "+err.Error())
 				return
 			}
@@ -64,32 +65,23 @@ func RegisterCommentRoutes(rg *gin.RouterGroup) {
 				return
 			}
 
-			pageStr := c.DefaultQuery("page", "1")
-			pageSizeStr := c.DefaultQuery("page_size", "10")
-
-			page, _ := strconv.Atoi(pageStr)
-			pageSize, _ := strconv.Atoi(pageSizeStr)
-
-			if page < 1 {
-				page = 1
-			}
-			if pageSize < 1 || pageSize > 100 {
-				pageSize = 10
+			req, err := parsePageRequest(c, 10)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
 			}
 
-			comments, total, err := service.GetCommentsByArticle(uint(articleID), page, pageSize)
+			comments, page, err := service.GetCommentsByArticle(uint(articleID), req)
 			if err != nil {
+				if errors.Is(err, service.ErrInvalidCursor) {
+					utils.Error(c, http.StatusBadRequest, err.Error())
+					return
+				}
 				utils.Error(c, http.StatusInternalServerError, "获取评论列表失败")
 				return
 			}
 
-			response := map[string]interface{}{
-				"comments":  comments,
-				"total":     total,
-				"page":      page,
-				"page_size": pageSize,
-			}
-			utils.Success(c, response)
+			utils.Success(c, pageResponse("comments", comments, req, page))
 		})
 
 		// 根据ID获取单条评论
@@ -111,7 +103,7 @@ func RegisterCommentRoutes(rg *gin.RouterGroup) {
 		})
 
 		// 删除评论
-		comment.DELETE("/:id", func(c *gin.Context) {
+		comment.DELETE("/:id", middleware.RequireScope(model.ScopeCommentsWrite), func(c *gin.Context) {
 			idParam := c.Param("id")
 			id, err := strconv.ParseUint(idParam, 10, 32)
 			if err != nil {
@@ -119,7 +111,24 @@ func RegisterCommentRoutes(rg *gin.RouterGroup) {
 				return
 			}
 
-			if err := service.DeleteComment(uint(id)); err != nil {
+			// 获取用户ID（从认证中间件设置的上下文）
+			userID, exists := c.Get("user_id")
+			if !exists {
+				utils.Error(c, http.StatusUnauthorized, "请先登录")
+				return
+			}
+
+			// 检查是否有权删除该评论
+			if err := service.CheckCommentPermission(uint(id), userID.(uint), c.GetString("role")); err != nil {
+				if errors.Is(err, service.ErrPermissionDenied) {
+					utils.Error(c, http.StatusForbidden, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			if err := service.DeleteComment(uint(id), auditContext(c)); err != nil {
 				utils.Error(c, http.StatusInternalServerError, "删除评论失败: "+err.Error())
 				return
 			}
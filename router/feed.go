@@ -0,0 +1,39 @@
+package router
+
+import (
+	"errors"
+	"gin-blog-system/middleware"
+	"gin-blog-system/model"
+	"gin-blog-system/service"
+	"gin-blog-system/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// RegisterFeedRoutes 注册个性化信息流路由
+func RegisterFeedRoutes(rg *gin.RouterGroup) {
+	// 关注的作者和标签下的最新文章，使用游标分页
+	rg.GET("/feed", middleware.AuthMiddleware(model.ScopeArticlesRead), func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if limit < 1 || limit > 100 {
+			limit = 10
+		}
+
+		articles, nextCursor, err := service.GetFeed(c.GetUint("user_id"), c.Query("cursor"), limit)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidCursor) {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			utils.Error(c, http.StatusInternalServerError, "获取信息流失败")
+			return
+		}
+
+		response := map[string]interface{}{
+			"articles":    articles,
+			"next_cursor": nextCursor,
+		}
+		utils.Success(c, response)
+	})
+}
@@ -0,0 +1,152 @@
+package router
+
+import (
+	"errors"
+	"gin-blog-system/middleware"
+	"gin-blog-system/service"
+	"gin-blog-system/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// RegisterOAuthRoutes 注册第三方登录（OpenID Connect）相关路由
+func RegisterOAuthRoutes(rg *gin.RouterGroup) {
+	oauth := rg.Group("/auth/oauth")
+	{
+		// 获取已配置的身份提供方
+		oauth.GET("/providers", func(c *gin.Context) {
+			utils.Success(c, service.ListOAuthProviders())
+		})
+
+		// 跳转到身份提供方登录
+		oauth.GET("/:provider/login", func(c *gin.Context) {
+			authURL, binding, err := service.BuildOAuthAuthURL(c.Param("provider"), 0)
+			if err != nil {
+				if errors.Is(err, service.ErrUnknownOAuthProvider) {
+					utils.Error(c, http.StatusNotFound, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusInternalServerError, "生成授权地址失败: "+err.Error())
+				return
+			}
+			setOAuthBindingCookie(c, binding, int(service.OAuthStateTTL.Seconds()))
+			c.Redirect(http.StatusFound, authURL)
+		})
+
+		// 已登录用户获取绑定第三方账号的授权地址
+		oauth.GET("/:provider/link", middleware.AuthMiddleware(), middleware.DenyImpersonation(), func(c *gin.Context) {
+			authURL, binding, err := service.BuildOAuthAuthURL(c.Param("provider"), c.GetUint("user_id"))
+			if err != nil {
+				if errors.Is(err, service.ErrUnknownOAuthProvider) {
+					utils.Error(c, http.StatusNotFound, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusInternalServerError, "生成授权地址失败: "+err.Error())
+				return
+			}
+			setOAuthBindingCookie(c, binding, int(service.OAuthStateTTL.Seconds()))
+			utils.Success(c, map[string]interface{}{
+				"auth_url": authURL,
+			})
+		})
+
+		// 身份提供方回调，必须由发起授权的浏览器完成
+		oauth.GET("/:provider/callback", func(c *gin.Context) {
+			if errMsg := c.Query("error"); errMsg != "" {
+				utils.Error(c, http.StatusUnauthorized, "第三方登录失败: "+errMsg)
+				return
+			}
+
+			state := c.Query("state")
+			code := c.Query("code")
+			if state == "" || code == "" {
+				utils.Error(c, http.StatusBadRequest, "缺少 state 或 code 参数")
+				return
+			}
+
+			binding, _ := c.Cookie(oauthBindingCookie)
+			setOAuthBindingCookie(c, "", -1)
+
+			user, err := service.HandleOAuthCallback(c.Param("provider"), state, code, binding, auditContext(c))
+			if err != nil {
+				switch {
+				case errors.Is(err, service.ErrUnknownOAuthProvider):
+					utils.Error(c, http.StatusNotFound, err.Error())
+				case errors.Is(err, service.ErrIdentityEmailTaken):
+					utils.Error(c, http.StatusConflict, err.Error())
+				case errors.Is(err, service.ErrRegistrationClosed), errors.Is(err, service.ErrPendingApproval),
+					errors.Is(err, service.ErrIdentityEmailMissing):
+					utils.Error(c, http.StatusForbidden, err.Error())
+				default:
+					utils.Error(c, http.StatusUnauthorized, err.Error())
+				}
+				return
+			}
+
+			// 启用两步验证的用户同样需要先通过 /api/auth/2fa/verify 兑换挑战令牌
+			if user.TwoFactorEnabled {
+				challengeToken, expiresIn, err := service.CreateTwoFactorChallenge(user.ID)
+				if err != nil {
+					utils.Error(c, http.StatusInternalServerError, "创建两步验证挑战失败: "+err.Error())
+					return
+				}
+				utils.Success(c, map[string]interface{}{
+					"two_factor_required": true,
+					"challenge_token":     challengeToken,
+					"expires_in":          expiresIn,
+				})
+				return
+			}
+
+			tokens, err := service.IssueTokenPair(user, c.ClientIP(), c.Request.UserAgent())
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "生成令牌失败: "+err.Error())
+				return
+			}
+
+			utils.Success(c, map[string]interface{}{
+				"user":          user.ConvertToUserResponse(),
+				"token":         tokens.AccessToken,
+				"refresh_token": tokens.RefreshToken,
+				"expires_in":    tokens.ExpiresIn,
+			})
+		})
+
+		// 获取当前用户绑定的第三方账号
+		oauth.GET("/identities", middleware.AuthMiddleware(), func(c *gin.Context) {
+			identities, err := service.ListUserIdentities(c.GetUint("user_id"))
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取绑定列表失败: "+err.Error())
+				return
+			}
+			utils.Success(c, identities)
+		})
+
+		// 解除第三方账号绑定
+		oauth.DELETE("/identities/:id", middleware.AuthMiddleware(), middleware.DenyImpersonation(), func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的绑定ID")
+				return
+			}
+
+			if err := service.UnlinkIdentity(c.GetUint("user_id"), uint(id)); err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			utils.Success(c, "已解除绑定")
+		})
+	}
+}
+
+// oauthBindingCookie 将授权请求绑定到发起授权的浏览器的 Cookie 名称
+const oauthBindingCookie = "oauth_binding"
+
+// setOAuthBindingCookie 写入（maxAge<0 时清除）浏览器绑定 Cookie。
+// 身份提供方的回调是跨站的顶层跳转，因此使用 SameSite=Lax
+func setOAuthBindingCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthBindingCookie, value, maxAge, "/api/auth/oauth", "", c.Request.TLS != nil, true)
+}
@@ -0,0 +1,57 @@
+package router
+
+import (
+	"errors"
+	"gin-blog-system/service"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+// parsePageRequest 解析分页参数：提供 cursor 时使用游标分页，否则按 page 分页；
+// with_total 控制是否计算总数，页码分页默认计算，游标分页默认不计算
+func parsePageRequest(c *gin.Context, defaultPageSize int) (service.PageRequest, error) {
+	pageStr := c.DefaultQuery("page", "1")
+	pageSizeStr := c.DefaultQuery("page_size", strconv.Itoa(defaultPageSize))
+
+	page, _ := strconv.Atoi(pageStr)
+	pageSize, _ := strconv.Atoi(pageSizeStr)
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = defaultPageSize
+	}
+
+	req := service.PageRequest{
+		Page:      page,
+		PageSize:  pageSize,
+		Cursor:    c.Query("cursor"),
+		WithTotal: c.Query("cursor") == "",
+	}
+	if value := c.Query("with_total"); value != "" {
+		withTotal, err := strconv.ParseBool(value)
+		if err != nil {
+			return req, errors.New("无效的 with_total 参数，应为 true 或 false")
+		}
+		req.WithTotal = withTotal
+	}
+	return req, nil
+}
+
+// pageResponse 构造分页列表的响应：页码分页时返回 page，计算了总数时返回 total
+func pageResponse(key string, items interface{}, req service.PageRequest, page *service.PageResult) map[string]interface{} {
+	response := map[string]interface{}{
+		key:           items,
+		"page_size":   req.PageSize,
+		"next_cursor": page.NextCursor,
+		"prev_cursor": page.PrevCursor,
+	}
+	if req.Cursor == "" {
+		response["page"] = req.Page
+	}
+	if page.Total != nil {
+		response["total"] = *page.Total
+	}
+	return response
+}
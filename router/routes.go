@@ -1,7 +1,11 @@
 package router
 
 import (
+	"gin-blog-system/service"
 	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/url"
+	"path"
 )
 
 // RegisterRoutes 注册所有路由
@@ -17,9 +21,12 @@ func RegisterRoutes(r *gin.Engine) {
 				"endpoints": map[string]string{
 					"auth":       "/api/auth",
 					"articles":   "/api/articles",
+					"series":     "/api/series",
 					"categories": "/api/categories",
 					"tags":       "/api/tags",
 					"upload":     "/api/upload",
+					"users":      "/api/users",
+					"search":     "/api/search",
 				},
 			})
 		})
@@ -37,10 +44,36 @@ func RegisterRoutes(r *gin.Engine) {
 		})
 
 		RegisterAuthRoutes(api)
+		RegisterOAuthRoutes(api)
 		RegisterArticleRoutes(api)
+		RegisterWorkflowRoutes(api)
+		RegisterSeriesRoutes(api)
 		RegisterCategoryRoutes(api)
 		RegisterTagsRoutes(api)
 		RegisterUploadRoutes(api)
 		RegisterCommentRoutes(api)
+		RegisterAdminRoutes(api)
+		RegisterUserRoutes(api)
+		RegisterFeedRoutes(api)
+		RegisterSearchRoutes(api)
 	}
 }
+
+// auditContext 根据请求构造审计上下文：当前用户、模拟登录的管理员、客户端IP和请求ID
+func auditContext(c *gin.Context) service.AuditContext {
+	return service.AuditContext{
+		ActorID:        c.GetUint("user_id"),
+		ImpersonatorID: c.GetUint("impersonator_id"),
+		IP:             c.ClientIP(),
+		RequestID:      c.GetString("request_id"),
+	}
+}
+
+// redirectToSlug 将通过旧 slug 访问的请求永久重定向到当前 slug 对应的地址（保留查询参数）
+func redirectToSlug(c *gin.Context, slug string) {
+	location := url.URL{
+		Path:     path.Join(path.Dir(c.Request.URL.Path), slug),
+		RawQuery: c.Request.URL.RawQuery,
+	}
+	c.Redirect(http.StatusMovedPermanently, location.String())
+}
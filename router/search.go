@@ -0,0 +1,47 @@
+package router
+
+import (
+	"errors"
+	"gin-blog-system/service"
+	"gin-blog-system/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// RegisterSearchRoutes 注册文章搜索路由
+func RegisterSearchRoutes(rg *gin.RouterGroup) {
+	// 全文搜索已发布的文章，按相关度排序
+	rg.GET("/search", func(c *gin.Context) {
+		pageStr := c.DefaultQuery("page", "1")
+		pageSizeStr := c.DefaultQuery("page_size", "10")
+
+		page, _ := strconv.Atoi(pageStr)
+		pageSize, _ := strconv.Atoi(pageSizeStr)
+
+		if page < 1 {
+			page = 1
+		}
+		if pageSize < 1 || pageSize > 100 {
+			pageSize = 10
+		}
+
+		results, total, err := service.SearchArticles(c.Query("q"), page, pageSize)
+		if err != nil {
+			if errors.Is(err, service.ErrEmptySearchQuery) {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			utils.Error(c, http.StatusInternalServerError, "搜索文章失败")
+			return
+		}
+
+		response := map[string]interface{}{
+			"results":   results,
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		}
+		utils.Success(c, response)
+	})
+}
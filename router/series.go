@@ -0,0 +1,263 @@
+package router
+
+import (
+	"errors"
+	"gin-blog-system/middleware"
+	"gin-blog-system/model"
+	"gin-blog-system/service"
+	"gin-blog-system/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// RegisterSeriesRoutes 注册文章系列相关路由
+func RegisterSeriesRoutes(rg *gin.RouterGroup) {
+	series := rg.Group("/series", middleware.AuthMiddleware(model.ScopeArticlesRead, model.ScopeArticlesWrite))
+	{
+		// 分页获取系列列表，可按创建者过滤
+		series.GET("", func(c *gin.Context) {
+			req, err := parsePageRequest(c, 10)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			var userID uint64
+			if userParam := c.Query("user_id"); userParam != "" {
+				if userID, err = strconv.ParseUint(userParam, 10, 32); err != nil {
+					utils.Error(c, http.StatusBadRequest, "无效的用户ID")
+					return
+				}
+			}
+
+			seriesList, page, err := service.GetAllSeries(uint(userID), req)
+			if err != nil {
+				if errors.Is(err, service.ErrInvalidCursor) {
+					utils.Error(c, http.StatusBadRequest, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusInternalServerError, "获取系列列表失败")
+				return
+			}
+
+			utils.Success(c, pageResponse("series", seriesList, req, page))
+		})
+
+		// 获取系列及其中按顺序排列的文章
+		series.GET("/:id", func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的系列ID")
+				return
+			}
+
+			result, err := service.GetSeriesByID(uint(id), c.GetUint("user_id"), c.GetString("role"))
+			if err != nil {
+				if errors.Is(err, service.ErrSeriesNotFound) {
+					utils.Error(c, http.StatusNotFound, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusInternalServerError, "获取系列失败")
+				return
+			}
+
+			utils.Success(c, result)
+		})
+
+		// SeriesRequest 创建或更新系列的请求
+		type SeriesRequest struct {
+			Title       string `json:"title" binding:"required"`
+			Description string `json:"description"`
+			Cover       string `json:"cover"`
+			ArticleIDs  []uint `json:"article_ids"` // 按顺序排列的文章，仅创建时使用
+		}
+
+		// 创建系列
+		series.POST("", middleware.RequireScope(model.ScopeArticlesWrite), middleware.RequirePermission(model.PermArticleCreate), func(c *gin.Context) {
+			var req SeriesRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			newSeries := model.Series{
+				Title:       req.Title,
+				Description: req.Description,
+				Cover:       req.Cover,
+				UserID:      c.GetUint("user_id"),
+			}
+			if err := service.CreateSeries(&newSeries, req.ArticleIDs, c.GetString("role"), auditContext(c)); err != nil {
+				respondSeriesError(c, err, "创建系列失败")
+				return
+			}
+
+			created, err := service.GetSeriesByID(newSeries.ID, c.GetUint("user_id"), c.GetString("role"))
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取创建的系列失败")
+				return
+			}
+
+			utils.Success(c, created)
+		})
+
+		// 更新系列的标题、简介和封面
+		series.PUT("/:id", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
+			id, ok := requireSeriesEditor(c)
+			if !ok {
+				return
+			}
+
+			var req SeriesRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			seriesData := model.Series{
+				Title:       req.Title,
+				Description: req.Description,
+				Cover:       req.Cover,
+			}
+			if err := service.UpdateSeries(id, &seriesData, auditContext(c)); err != nil {
+				respondSeriesError(c, err, "更新系列失败")
+				return
+			}
+
+			updated, err := service.GetSeriesByID(id, c.GetUint("user_id"), c.GetString("role"))
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取更新后的系列失败")
+				return
+			}
+
+			utils.Success(c, updated)
+		})
+
+		// 删除系列，系列中的文章保留
+		series.DELETE("/:id", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
+			id, ok := requireSeriesEditor(c)
+			if !ok {
+				return
+			}
+
+			if err := service.DeleteSeries(id, auditContext(c)); err != nil {
+				respondSeriesError(c, err, "删除系列失败")
+				return
+			}
+
+			utils.Success(c, nil)
+		})
+
+		// 按给定顺序替换系列中的文章，用于调整顺序
+		series.PUT("/:id/articles", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
+			id, ok := requireSeriesEditor(c)
+			if !ok {
+				return
+			}
+
+			var req struct {
+				ArticleIDs []uint `json:"article_ids"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			if err := service.SetSeriesArticles(id, req.ArticleIDs, c.GetUint("user_id"), c.GetString("role"), auditContext(c)); err != nil {
+				respondSeriesError(c, err, "更新系列文章失败")
+				return
+			}
+
+			updated, err := service.GetSeriesByID(id, c.GetUint("user_id"), c.GetString("role"))
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取更新后的系列失败")
+				return
+			}
+
+			utils.Success(c, updated)
+		})
+
+		// 将文章添加到系列末尾
+		series.POST("/:id/articles", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
+			id, ok := requireSeriesEditor(c)
+			if !ok {
+				return
+			}
+
+			var req struct {
+				ArticleID uint `json:"article_id" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			if err := service.AddSeriesArticle(id, req.ArticleID, c.GetUint("user_id"), c.GetString("role"), auditContext(c)); err != nil {
+				respondSeriesError(c, err, "添加系列文章失败")
+				return
+			}
+
+			updated, err := service.GetSeriesByID(id, c.GetUint("user_id"), c.GetString("role"))
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取更新后的系列失败")
+				return
+			}
+
+			utils.Success(c, updated)
+		})
+
+		// 将文章移出系列
+		series.DELETE("/:id/articles/:article_id", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
+			id, ok := requireSeriesEditor(c)
+			if !ok {
+				return
+			}
+
+			articleID, err := strconv.ParseUint(c.Param("article_id"), 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的文章ID")
+				return
+			}
+
+			if err := service.RemoveSeriesArticle(id, uint(articleID), c.GetUint("user_id"), c.GetString("role"), auditContext(c)); err != nil {
+				respondSeriesError(c, err, "移除系列文章失败")
+				return
+			}
+
+			utils.Success(c, nil)
+		})
+	}
+}
+
+// requireSeriesEditor 解析路径中的系列ID并检查当前用户是否有权编辑该系列，无权时直接写入错误响应
+func requireSeriesEditor(c *gin.Context) (uint, bool) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "无效的系列ID")
+		return 0, false
+	}
+
+	if err := service.CheckSeriesPermission(uint(id), c.GetUint("user_id"), c.GetString("role")); err != nil {
+		respondSeriesError(c, err, "获取系列失败")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondSeriesError 根据系列操作的错误类型写入错误响应
+func respondSeriesError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrSeriesNotFound), errors.Is(err, service.ErrArticleNotInSeries):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrPermissionDenied):
+		utils.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrArticleInOtherSeries):
+		utils.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidSeriesArticles):
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.Error(c, http.StatusInternalServerError, message+": "+err.Error())
+	}
+}
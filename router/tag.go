@@ -1,6 +1,7 @@
 package router
 
 import (
+	"gin-blog-system/middleware"
 	"gin-blog-system/model"
 	"gin-blog-system/service"
 	"gin-blog-system/utils"
@@ -12,6 +13,8 @@ import (
 // RegisterTagsRoutes 注册标签相关路由
 func RegisterTagsRoutes(rg *gin.RouterGroup) {
 	tag := rg.Group("/tags")
+	// 标签的增删改仅限拥有管理权限的角色
+	manage := tag.Group("", middleware.AuthMiddleware(), middleware.RequirePermission(model.PermTaxonomyManage))
 	{
 		// 获取标签列表
 		tag.GET("", func(c *gin.Context) {
@@ -23,6 +26,50 @@ func RegisterTagsRoutes(rg *gin.RouterGroup) {
 			utils.Success(c, tags)
 		})
 
+		// 获取当前用户关注的标签
+		tag.GET("/followed", middleware.AuthMiddleware(), func(c *gin.Context) {
+			tags, err := service.GetFollowedTags(c.GetUint("user_id"))
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取关注的标签失败")
+				return
+			}
+			utils.Success(c, tags)
+		})
+
+		// 关注标签
+		tag.POST("/:id/follow", middleware.AuthMiddleware(), func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的标签ID")
+				return
+			}
+
+			if err := service.FollowTag(c.GetUint("user_id"), uint(id)); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, "关注成功")
+		})
+
+		// 取消关注标签
+		tag.DELETE("/:id/follow", middleware.AuthMiddleware(), func(c *gin.Context) {
+			idParam := c.Param("id")
+			id, err := strconv.ParseUint(idParam, 10, 32)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, "无效的标签ID")
+				return
+			}
+
+			if err := service.UnfollowTag(c.GetUint("user_id"), uint(id)); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, "已取消关注")
+		})
+
 		// 根据ID获取单个标签
 		tag.GET("/:id", func(c *gin.Context) {
 			idParam := c.Param("id")
@@ -41,15 +88,36 @@ func RegisterTagsRoutes(rg *gin.RouterGroup) {
 			utils.Success(c, tag)
 		})
 
+		// 根据 slug 获取标签，旧 slug 永久重定向到当前地址
+		tag.GET("/slug/:slug", func(c *gin.Context) {
+			id, slug, err := service.ResolveSlug(service.SlugTargetTag, c.Param("slug"))
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			if slug != c.Param("slug") {
+				redirectToSlug(c, slug)
+				return
+			}
+
+			tag, err := service.GetTagByID(id)
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			utils.Success(c, tag)
+		})
+
 		// 创建标签
-		tag.POST("", func(c *gin.Context) {
+		manage.POST("", func(c *gin.Context) {
 			var tag model.Tag
 			if err := c.ShouldBindJSON(&tag); err != nil {
 				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
 				return
 			}
 
-			if err := service.CreateTag(&tag); err != nil {
+			if err := service.CreateTag(&tag, auditContext(c)); err != nil {
 				utils.Error(c, http.StatusInternalServerError, "创建标签失败: "+err.Error())
 				return
 			}
@@ -58,7 +126,7 @@ func RegisterTagsRoutes(rg *gin.RouterGroup) {
 		})
 
 		// 更新标签
-		tag.PUT("/:id", func(c *gin.Context) {
+		manage.PUT("/:id", func(c *gin.Context) {
 			idParam := c.Param("id")
 			id, err := strconv.ParseUint(idParam, 10, 32)
 			if err != nil {
@@ -72,7 +140,7 @@ func RegisterTagsRoutes(rg *gin.RouterGroup) {
 				return
 			}
 
-			if err := service.UpdateTag(uint(id), &tagData); err != nil {
+			if err := service.UpdateTag(uint(id), &tagData, auditContext(c)); err != nil {
 				utils.Error(c, http.StatusInternalServerError, "更新标签失败: "+err.Error())
 				return
 			}
@@ -87,7 +155,7 @@ func RegisterTagsRoutes(rg *gin.RouterGroup) {
 		})
 
 		// 删除标签
-		tag.DELETE("/:id", func(c *gin.Context) {
+		manage.DELETE("/:id", func(c *gin.Context) {
 			idParam := c.Param("id")
 			id, err := strconv.ParseUint(idParam, 10, 32)
 			if err != nil {
@@ -95,7 +163,7 @@ func RegisterTagsRoutes(rg *gin.RouterGroup) {
 				return
 			}
 
-			if err := service.DeleteTag(uint(id)); err != nil {
+			if err := service.DeleteTag(uint(id), auditContext(c)); err != nil {
 				utils.Error(c, http.StatusInternalServerError, "删除标签失败: "+err.Error())
 				return
 			}
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"gin-blog-system/middleware"
+	"gin-blog-system/model"
 	"gin-blog-system/service"
 	"gin-blog-system/utils"
 	"github.com/gin-gonic/gin"
@@ -10,7 +12,7 @@ import (
 
 // RegisterUploadRoutes 注册上传相关路由
 func RegisterUploadRoutes(rg *gin.RouterGroup) {
-	upload := rg.Group("/upload")
+	upload := rg.Group("/upload", middleware.AuthMiddleware(model.ScopeUploadsWrite))
 	{
 		upload.POST("/image", func(c *gin.Context) {
 			// 获取上传的文件
@@ -27,7 +29,7 @@ func RegisterUploadRoutes(rg *gin.RouterGroup) {
 			}
 
 			// 保存文件
-			filePath, err := service.UploadImage(file)
+			filePath, err := service.UploadImage(file, c.GetUint("user_id"))
 			if err != nil {
 				utils.Error(c, http.StatusInternalServerError, "上传文件失败: "+err.Error())
 				return
@@ -57,7 +59,7 @@ func RegisterUploadRoutes(rg *gin.RouterGroup) {
 			}
 
 			// 保存文件
-			filePath, err := service.UploadImage(file) // 使用相同的方法处理普通文件
+			filePath, err := service.UploadImage(file, c.GetUint("user_id")) // 使用相同的方法处理普通文件
 			if err != nil {
 				utils.Error(c, http.StatusInternalServerError, "上传文件失败: "+err.Error())
 				return
@@ -0,0 +1,262 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"gin-blog-system/middleware"
+	"gin-blog-system/model"
+	"gin-blog-system/service"
+	"gin-blog-system/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RegisterUserRoutes 注册用户资料相关路由
+func RegisterUserRoutes(rg *gin.RouterGroup) {
+	users := rg.Group("/users")
+	{
+		// 获取当前用户信息
+		users.GET("/me", middleware.AuthMiddleware(), func(c *gin.Context) {
+			user, err := service.GetUserByID(c.GetUint("user_id"))
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			utils.Success(c, user.ConvertToUserResponse())
+		})
+
+		// 修改当前用户的资料，修改密码需要提供当前密码
+		users.PATCH("/me", middleware.AuthMiddleware(), func(c *gin.Context) {
+			var req struct {
+				Nickname        *string `json:"nickname"`
+				Avatar          *string `json:"avatar"`
+				Bio             *string `json:"bio"`
+				CurrentPassword string  `json:"current_password"`
+				NewPassword     string  `json:"new_password"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			userID := c.GetUint("user_id")
+			profile := service.ProfileUpdate{
+				Nickname: req.Nickname,
+				Avatar:   req.Avatar,
+				Bio:      req.Bio,
+			}
+
+			// 先校验资料，资料无效时不修改密码（修改密码会作废全部令牌，无法回滚）
+			if err := profile.Validate(); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			// 再修改密码，当前密码错误时不修改任何资料
+			passwordChanged := false
+			if req.NewPassword != "" {
+				if c.GetUint("impersonator_id") != 0 {
+					utils.Error(c, http.StatusForbidden, "模拟登录状态下不允许修改密码")
+					return
+				}
+				if req.CurrentPassword == "" {
+					utils.Error(c, http.StatusBadRequest, "修改密码需要提供当前密码")
+					return
+				}
+				if err := service.ChangePassword(userID, req.CurrentPassword, req.NewPassword); err != nil {
+					utils.Error(c, http.StatusBadRequest, err.Error())
+					return
+				}
+				passwordChanged = true
+			}
+
+			user, err := service.UpdateUserProfile(userID, profile)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			if passwordChanged {
+				utils.Result(c, http.StatusOK, user.ConvertToUserResponse(), "密码已修改，请重新登录")
+				return
+			}
+			utils.Success(c, user.ConvertToUserResponse())
+		})
+
+		// 导出当前用户的全部个人数据（ZIP归档）
+		users.GET("/me/export", middleware.AuthMiddleware(), middleware.DenyImpersonation(), func(c *gin.Context) {
+			export, err := service.ExportUserData(c.GetUint("user_id"))
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "导出数据失败: "+err.Error())
+				return
+			}
+
+			filename := fmt.Sprintf("user-%d-export-%s.zip", c.GetUint("user_id"), time.Now().Format("20060102150405"))
+			c.Header("Content-Type", "application/zip")
+			c.Header("Content-Disposition", "attachment; filename="+filename)
+			c.Status(http.StatusOK)
+			if err := export.WriteZip(c.Writer); err != nil {
+				fmt.Printf("写入导出文件失败: %v\n", err)
+			}
+		})
+
+		// 注销当前账号：需要确认密码，数据删除在后台执行，可通过返回的任务ID查询进度
+		users.DELETE("/me", middleware.AuthMiddleware(), middleware.DenyImpersonation(), func(c *gin.Context) {
+			var req struct {
+				Password string `json:"password" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			userID := c.GetUint("user_id")
+			user, err := service.GetUserByID(userID)
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			if !service.CheckPasswordHash(req.Password, user.Password) {
+				utils.Error(c, http.StatusBadRequest, "密码错误")
+				return
+			}
+
+			job, err := service.RequestUserErasure(userID, userID)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Result(c, http.StatusAccepted, job, "注销任务已创建")
+		})
+
+		// 查询账号注销任务进度
+		users.GET("/erasure/:job_id", func(c *gin.Context) {
+			job, err := service.GetErasureJob(c.Param("job_id"))
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			utils.Success(c, job)
+		})
+
+		// 以下路由共用 :user 路径参数：纯数字时为用户ID，否则为用户名
+
+		// 获取用户公开主页
+		users.GET("/:user", func(c *gin.Context) {
+			profile, err := service.GetUserProfile(c.Param("user"))
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			utils.Success(c, profile)
+		})
+
+		// 获取用户已发布的文章
+		users.GET("/:user/articles", func(c *gin.Context) {
+			user, err := service.GetUserByRef(c.Param("user"))
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			req, err := parsePageRequest(c, 10)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			articles, page, err := service.GetArticlesByUser(user.ID, req)
+			if err != nil {
+				if errors.Is(err, service.ErrInvalidCursor) {
+					utils.Error(c, http.StatusBadRequest, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusInternalServerError, "获取文章列表失败")
+				return
+			}
+
+			utils.Success(c, pageResponse("articles", articles, req, page))
+		})
+
+		// 关注用户
+		users.POST("/:user/follow", middleware.AuthMiddleware(), func(c *gin.Context) {
+			user, err := service.GetUserByRef(c.Param("user"))
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			if err := service.FollowUser(c.GetUint("user_id"), user.ID); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, "关注成功")
+		})
+
+		// 取消关注用户
+		users.DELETE("/:user/follow", middleware.AuthMiddleware(), func(c *gin.Context) {
+			user, err := service.GetUserByRef(c.Param("user"))
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+
+			if err := service.UnfollowUser(c.GetUint("user_id"), user.ID); err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			utils.Success(c, "已取消关注")
+		})
+
+		// 获取用户的粉丝列表
+		users.GET("/:user/followers", func(c *gin.Context) {
+			listFollowUsers(c, service.GetFollowers)
+		})
+
+		// 获取用户关注的人
+		users.GET("/:user/following", func(c *gin.Context) {
+			listFollowUsers(c, service.GetFollowing)
+		})
+	}
+}
+
+// listFollowUsers 分页返回关注关系列表
+func listFollowUsers(c *gin.Context, list func(userID uint, page, pageSize int) ([]model.PublicUserResponse, int64, error)) {
+	user, err := service.GetUserByRef(c.Param("user"))
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	pageStr := c.DefaultQuery("page", "1")
+	pageSizeStr := c.DefaultQuery("page_size", "20")
+
+	page, _ := strconv.Atoi(pageStr)
+	pageSize, _ := strconv.Atoi(pageSizeStr)
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	users, total, err := list(user.ID, page, pageSize)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "获取列表失败")
+		return
+	}
+
+	response := map[string]interface{}{
+		"users":     users,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	}
+	utils.Success(c, response)
+}
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"gin-blog-system/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// RegisterWellKnownRoutes 注册 /.well-known 下的公开路由
+func RegisterWellKnownRoutes(r *gin.Engine) {
+	// JWT验签公钥（JWKS），其他服务可据此校验本服务签发的访问令牌
+	r.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		set, err := service.PublicJWKS()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to build JWKS: " + err.Error(),
+			})
+			return
+		}
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, set)
+	})
+}
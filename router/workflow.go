@@ -0,0 +1,222 @@
+package router
+
+import (
+	"errors"
+	"gin-blog-system/middleware"
+	"gin-blog-system/model"
+	"gin-blog-system/service"
+	"gin-blog-system/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RegisterWorkflowRoutes 注册文章编辑流程（提交、审核、发布、归档）相关路由
+func RegisterWorkflowRoutes(rg *gin.RouterGroup) {
+	article := rg.Group("/articles", middleware.AuthMiddleware(model.ScopeArticlesRead, model.ScopeArticlesWrite))
+	{
+		// 获取等待当前用户审核的文章（需审核权限）
+		article.GET("/review-queue", func(c *gin.Context) {
+			pageStr := c.DefaultQuery("page", "1")
+			pageSizeStr := c.DefaultQuery("page_size", "10")
+
+			page, _ := strconv.Atoi(pageStr)
+			pageSize, _ := strconv.Atoi(pageSizeStr)
+
+			if page < 1 {
+				page = 1
+			}
+			if pageSize < 1 || pageSize > 100 {
+				pageSize = 10
+			}
+
+			articles, total, err := service.GetReviewQueue(c.GetUint("user_id"), c.GetString("role"), page, pageSize)
+			if err != nil {
+				if errors.Is(err, service.ErrPermissionDenied) {
+					utils.Error(c, http.StatusForbidden, err.Error())
+					return
+				}
+				utils.Error(c, http.StatusInternalServerError, "获取待审核文章失败")
+				return
+			}
+
+			response := map[string]interface{}{
+				"articles":  articles,
+				"total":     total,
+				"page":      page,
+				"page_size": pageSize,
+			}
+			utils.Success(c, response)
+		})
+
+		// 获取文章的流程状态和当前用户可以执行的操作
+		article.GET("/:id/workflow", func(c *gin.Context) {
+			id, ok := requireVisibleArticle(c)
+			if !ok {
+				return
+			}
+
+			workflow, err := service.GetArticleWorkflow(id, c.GetUint("user_id"), c.GetString("role"))
+			if err != nil {
+				utils.Error(c, http.StatusNotFound, err.Error())
+				return
+			}
+			utils.Success(c, workflow)
+		})
+
+		// 执行流程操作：submit/withdraw/approve/request_changes/publish/archive/reopen
+		article.POST("/:id/workflow/:action", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
+			id, ok := requireVisibleArticle(c)
+			if !ok {
+				return
+			}
+
+			var req struct {
+				Comment     string     `json:"comment"`      // 审核意见，要求修改时必填
+				PublishAt   *time.Time `json:"publish_at"`   // 发布时间（RFC3339），晚于当前时间时定时发布
+				UnpublishAt *time.Time `json:"unpublish_at"` // 定时下线时间（RFC3339）
+			}
+			// 请求体可以为空
+			if c.Request.ContentLength != 0 {
+				if err := c.ShouldBindJSON(&req); err != nil {
+					utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+					return
+				}
+			}
+
+			input := service.TransitionInput{
+				Comment:     req.Comment,
+				PublishAt:   req.PublishAt,
+				UnpublishAt: req.UnpublishAt,
+			}
+			if _, err := service.TransitionArticle(id, c.Param("action"), c.GetString("role"), input, auditContext(c)); err != nil {
+				workflowError(c, err)
+				return
+			}
+
+			updatedArticle, err := service.GetArticleByID(id)
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取文章失败")
+				return
+			}
+			utils.Success(c, updatedArticle)
+		})
+
+		// 指定审核人（需审核权限），reviewer_id 为 0 时取消指定
+		article.PUT("/:id/reviewer", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
+			id, ok := requireVisibleArticle(c)
+			if !ok {
+				return
+			}
+
+			var req struct {
+				ReviewerID *uint `json:"reviewer_id" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			if err := service.AssignReviewer(id, *req.ReviewerID, c.GetString("role"), auditContext(c)); err != nil {
+				workflowError(c, err)
+				return
+			}
+
+			updatedArticle, err := service.GetArticleByID(id)
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取文章失败")
+				return
+			}
+			utils.Success(c, updatedArticle)
+		})
+
+		// 获取文章的审核意见（作者、审核人）
+		article.GET("/:id/reviews", func(c *gin.Context) {
+			id, ok := requireReviewAccess(c)
+			if !ok {
+				return
+			}
+
+			reviews, err := service.GetReviewComments(id)
+			if err != nil {
+				utils.Error(c, http.StatusInternalServerError, "获取审核意见失败")
+				return
+			}
+			utils.Success(c, reviews)
+		})
+
+		// 发表审核意见（作者、审核人），不改变文章状态
+		article.POST("/:id/reviews", middleware.RequireScope(model.ScopeArticlesWrite), func(c *gin.Context) {
+			id, ok := requireReviewAccess(c)
+			if !ok {
+				return
+			}
+
+			var req struct {
+				Content string `json:"content" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				utils.Error(c, http.StatusBadRequest, "参数绑定失败: "+err.Error())
+				return
+			}
+
+			review, err := service.CreateReviewComment(id, c.GetUint("user_id"), req.Content)
+			if err != nil {
+				utils.Error(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			utils.Success(c, review.ConvertToReviewCommentResponse())
+		})
+	}
+}
+
+// requireVisibleArticle 解析路径中的文章ID并检查当前用户能否看到该文章，不能时直接写入 404 响应
+func requireVisibleArticle(c *gin.Context) (uint, bool) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "无效的文章ID")
+		return 0, false
+	}
+
+	if err := service.CheckArticleVisible(uint(id), c.GetUint("user_id"), c.GetString("role")); err != nil {
+		utils.Error(c, http.StatusNotFound, err.Error())
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// requireReviewAccess 解析路径中的文章ID并检查当前用户能否查看审核意见，不能时直接写入错误响应
+func requireReviewAccess(c *gin.Context) (uint, bool) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "无效的文章ID")
+		return 0, false
+	}
+
+	if err := service.CheckReviewAccess(uint(id), c.GetUint("user_id"), c.GetString("role")); err != nil {
+		if errors.Is(err, service.ErrPermissionDenied) {
+			utils.Error(c, http.StatusForbidden, err.Error())
+			return 0, false
+		}
+		utils.Error(c, http.StatusNotFound, err.Error())
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// workflowError 将流程操作的错误转换为响应：无权操作 403，状态不允许 409，其余为参数错误
+func workflowError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied):
+		utils.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrInvalidTransition):
+		utils.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrUnknownArticleAction):
+		utils.Error(c, http.StatusNotFound, err.Error())
+	default:
+		utils.Error(c, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// accessTokenTouchInterval 令牌最近使用时间的最小更新间隔
+const accessTokenTouchInterval = time.Minute
+
+// ErrInvalidAccessToken 个人访问令牌无效
+var ErrInvalidAccessToken = errors.New("无效的访问令牌")
+
+// CreatePersonalAccessToken 创建个人访问令牌，令牌明文只在返回值中出现一次
+func CreatePersonalAccessToken(userID uint, name string, scopes []string, expiresAt *time.Time) (*model.PersonalAccessTokenResponse, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("令牌名称不能为空")
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("至少需要一个权限范围")
+	}
+	for _, scope := range scopes {
+		if !model.IsValidScope(scope) {
+			return nil, errors.New("无效的权限范围: " + scope)
+		}
+	}
+	if expiresAt != nil && expiresAt.Before(time.Now()) {
+		return nil, errors.New("过期时间不能早于当前时间")
+	}
+
+	secret, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+	token := model.PersonalAccessTokenPrefix + secret
+
+	record := model.PersonalAccessToken{
+		UserID:    userID,
+		Name:      truncateString(name, 100),
+		Prefix:    token[:len(model.PersonalAccessTokenPrefix)+8],
+		TokenHash: utils.HashToken(token),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: expiresAt,
+	}
+	if err := config.DB.Create(&record).Error; err != nil {
+		return nil, err
+	}
+
+	response := record.ConvertToPersonalAccessTokenResponse()
+	response.Token = token
+	return response, nil
+}
+
+// ListPersonalAccessTokens 获取用户未撤销的个人访问令牌
+func ListPersonalAccessTokens(userID uint) ([]model.PersonalAccessTokenResponse, error) {
+	var tokens []model.PersonalAccessToken
+	result := config.DB.Where("user_id = ? AND revoked_at IS NULL", userID).Order("created_at DESC").Find(&tokens)
+
+	// 转换为响应结构
+	responses := make([]model.PersonalAccessTokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = *token.ConvertToPersonalAccessTokenResponse()
+	}
+
+	return responses, result.Error
+}
+
+// RevokePersonalAccessToken 撤销用户的个人访问令牌
+func RevokePersonalAccessToken(userID, tokenID uint) error {
+	result := config.DB.Model(&model.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", tokenID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("令牌不存在")
+	}
+	return nil
+}
+
+// AuthenticatePersonalAccessToken 校验个人访问令牌，返回令牌记录和所属用户
+func AuthenticatePersonalAccessToken(token string) (*model.PersonalAccessToken, *model.User, error) {
+	var record model.PersonalAccessToken
+	result := config.DB.Where("token_hash = ?", utils.HashToken(token)).First(&record)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrInvalidAccessToken
+	}
+	if result.Error != nil {
+		return nil, nil, result.Error
+	}
+
+	now := time.Now()
+	if record.RevokedAt != nil || (record.ExpiresAt != nil && record.ExpiresAt.Before(now)) {
+		return nil, nil, ErrInvalidAccessToken
+	}
+
+	var user model.User
+	if err := config.DB.First(&user, record.UserID).Error; err != nil {
+		return nil, nil, ErrInvalidAccessToken
+	}
+	if user.Status != model.UserStatusActive {
+		return nil, nil, errors.New("账号已被禁用")
+	}
+
+	// 记录最近使用时间（限制更新频率）
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) > accessTokenTouchInterval {
+		config.DB.Model(&record).UpdateColumn("last_used_at", now)
+	}
+
+	return &record, &user, nil
+}
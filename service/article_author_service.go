@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidArticleAuthors 无效的署名作者列表
+var ErrInvalidArticleAuthors = errors.New("无效的署名作者列表")
+
+// ArticleAuthorInput 署名作者列表中的一项，列表顺序即展示顺序
+type ArticleAuthorInput struct {
+	UserID uint   `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// preloadArticleAuthors 按展示顺序预加载文章的署名作者
+func preloadArticleAuthors(db *gorm.DB) *gorm.DB {
+	return db.Preload("Authors", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Preload("Authors.User")
+}
+
+// isArticleAuthor 检查用户是否署名于文章（任何署名角色）
+func isArticleAuthor(articleID, userID uint) (bool, error) {
+	if userID == 0 {
+		return false, nil
+	}
+	var count int64
+	err := config.DB.Model(&model.ArticleAuthor{}).Where("article_id = ? AND user_id = ?", articleID, userID).Count(&count).Error
+	return count > 0, err
+}
+
+// articleIDsCreditedTo 用户署名的文章ID子查询
+func articleIDsCreditedTo(userID uint) *gorm.DB {
+	return config.DB.Model(&model.ArticleAuthor{}).Select("article_id").Where("user_id = ?", userID)
+}
+
+// createPrimaryAuthor 为新文章写入主作者署名
+func createPrimaryAuthor(tx *gorm.DB, article *model.Article) error {
+	return tx.Create(&model.ArticleAuthor{
+		ArticleID: article.ID,
+		UserID:    article.UserID,
+		Role:      model.AuthorRoleAuthor,
+	}).Error
+}
+
+// GetArticleAuthors 获取文章的署名作者（按展示顺序）
+func GetArticleAuthors(articleID uint) ([]model.AuthorResponse, error) {
+	var article model.Article
+	result := preloadArticleAuthors(config.DB.Preload("User")).Select("id", "user_id").First(&article, articleID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, errors.New("文章不存在")
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return article.ConvertToArticleResponse().Authors, nil
+}
+
+// SetArticleAuthors 按给定顺序替换文章的署名作者。
+// 列表必须包含主作者（文章的 UserID，角色为 author），其他作者不能使用 author 角色，同一用户只能署名一次
+func SetArticleAuthors(articleID uint, authors []ArticleAuthorInput, actx AuditContext) error {
+	var article model.Article
+	result := config.DB.Select("id", "user_id").First(&article, articleID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return errors.New("文章不存在")
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+
+	rows := make([]model.ArticleAuthor, len(authors))
+	userIDs := make([]uint, len(authors))
+	seen := make(map[uint]bool, len(authors))
+	for i, author := range authors {
+		if !model.IsValidAuthorRole(author.Role) {
+			return fmt.Errorf("%w：未知的署名角色 %q，可选 author、co-author、editor、translator", ErrInvalidArticleAuthors, author.Role)
+		}
+		if seen[author.UserID] {
+			return fmt.Errorf("%w：用户 %d 重复", ErrInvalidArticleAuthors, author.UserID)
+		}
+		seen[author.UserID] = true
+		if (author.UserID == article.UserID) != (author.Role == model.AuthorRoleAuthor) {
+			return fmt.Errorf("%w：author 角色只能是文章的主作者", ErrInvalidArticleAuthors)
+		}
+		rows[i] = model.ArticleAuthor{ArticleID: articleID, UserID: author.UserID, Role: author.Role, Position: i}
+		userIDs[i] = author.UserID
+	}
+	if !seen[article.UserID] {
+		return fmt.Errorf("%w：不能移除主作者", ErrInvalidArticleAuthors)
+	}
+
+	var count int64
+	if err := config.DB.Model(&model.User{}).Where("id IN ?", userIDs).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(userIDs) {
+		return fmt.Errorf("%w：用户不存在", ErrInvalidArticleAuthors)
+	}
+
+	var before []model.ArticleAuthor
+	if err := config.DB.Where("article_id = ?", articleID).Order("position ASC").Find(&before).Error; err != nil {
+		return err
+	}
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("article_id = ?", articleID).Delete(&model.ArticleAuthor{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordAudit(tx, actx, AuditArticleAuthors, AuditTargetArticle, articleID, auditAuthorList(before), auditAuthorList(rows)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// auditAuthorList 将署名列表转换为审计记录中的字符串（数组不参与变更对比），格式为 用户ID:角色
+func auditAuthorList(authors []model.ArticleAuthor) map[string]interface{} {
+	values := make([]string, len(authors))
+	for i, author := range authors {
+		values[i] = fmt.Sprintf("%d:%s", author.UserID, author.Role)
+	}
+	return map[string]interface{}{"authors": strings.Join(values, ",")}
+}
+
+// BackfillArticleAuthors 为升级前已有的文章补充主作者署名，服务启动时调用
+func BackfillArticleAuthors() error {
+	credited := config.DB.Model(&model.ArticleAuthor{}).Select("article_id").Where("role = ?", model.AuthorRoleAuthor)
+	var articles []model.Article
+	if err := config.DB.Select("id", "user_id").Where("id NOT IN (?)", credited).Find(&articles).Error; err != nil {
+		return err
+	}
+	for _, article := range articles {
+		if err := createPrimaryAuthor(config.DB, &article); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidListQuery 列表查询参数无效
+var ErrInvalidListQuery = errors.New("无效的查询参数")
+
+// articleListParams 文章列表支持的查询参数（分页参数由调用方解析）
+var articleListParams = []string{
+	"page", "page_size", "cursor", "with_total", "status", "category_id", "tag_ids", "tag_match", "author",
+	"created_from", "created_to", "updated_from", "updated_to", "sort", "fields",
+}
+
+// articleSortKeys 文章列表支持的排序字段
+var articleSortKeys = []string{
+	"id", "title", "created_at", "updated_at", "publish_at", "view_count", "like_count", "comment_count",
+}
+
+// articleListFields 文章列表可选的响应字段及其需要查询的列
+// 关联对象（user、category、tags、authors）通过预加载获取
+var articleListFields = map[string][]string{
+	"id":             {"id"},
+	"title":          {"title"},
+	"slug":           {"slug"},
+	"content":        {"content"},
+	"content_format": {"content_format"},
+	"content_html":   {"content_html"},
+	"summary":        {"summary"},
+	"cover":          {"cover"},
+	"status":         {"status"},
+	"state":          {"status"},
+	"reviewer_id":    {"reviewer_id"},
+	"publish_at":     {"publish_at"},
+	"unpublish_at":   {"unpublish_at"},
+	"view_count":     {"view_count"},
+	"like_count":     {"like_count"},
+	"comment_count":  {"comment_count"},
+	"user_id":        {"user_id"},
+	"user":           {"user_id"},
+	"category_id":    {"category_id"},
+	"category":       {"category_id"},
+	"tag_ids":        {},
+	"tags":           {},
+	"authors":        {},
+	"created_at":     {"created_at"},
+	"updated_at":     {"updated_at"},
+}
+
+// ArticleSort 文章列表的排序条件
+type ArticleSort struct {
+	Key  string // 排序字段，见 articleSortKeys
+	Desc bool   // 是否倒序
+}
+
+// ArticleListFilter 文章列表的过滤、排序和字段选择条件
+type ArticleListFilter struct {
+	Statuses     []int         // 文章状态，为空时只返回已发布的文章
+	AnyStatus    bool          // status=all，不按状态过滤（仍受可见性限制）
+	CategoryID   *uint         // 分类ID，0 表示未分类
+	TagIDs       []uint        // 标签ID
+	MatchAllTags bool          // 为 true 时文章需包含全部标签，否则包含任意一个即可
+	Author       string        // 作者的用户ID或用户名
+	CreatedFrom  *time.Time    // 创建时间范围（含）
+	CreatedTo    *time.Time    //
+	UpdatedFrom  *time.Time    // 更新时间范围（含）
+	UpdatedTo    *time.Time    //
+	Sort         []ArticleSort // 排序条件，为空时按创建时间倒序
+	Fields       []string      // 返回的字段，为空时返回全部字段
+}
+
+// ParseArticleListFilter 解析文章列表的查询参数，未知的参数、状态、排序字段或返回字段返回 ErrInvalidListQuery
+func ParseArticleListFilter(params url.Values) (ArticleListFilter, error) {
+	var filter ArticleListFilter
+
+	for key := range params {
+		if !containsString(articleListParams, key) {
+			return filter, fmt.Errorf("%w：不支持的参数 %s，可用的参数：%s", ErrInvalidListQuery, key, strings.Join(articleListParams, ", "))
+		}
+	}
+
+	for _, value := range splitListParam(params, "status") {
+		if value == "all" {
+			filter.AnyStatus = true
+			continue
+		}
+		status, ok := model.ParseArticleStatus(value)
+		if !ok {
+			return filter, fmt.Errorf("%w：无效的状态 %s，可用的状态：draft, submitted, changes_requested, approved, scheduled, published, archived, all",
+				ErrInvalidListQuery, value)
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+
+	if value := params.Get("category_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return filter, fmt.Errorf("%w：无效的分类ID %s", ErrInvalidListQuery, value)
+		}
+		categoryID := uint(id)
+		filter.CategoryID = &categoryID
+	}
+
+	for _, value := range splitListParam(params, "tag_ids") {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return filter, fmt.Errorf("%w：无效的标签ID %s", ErrInvalidListQuery, value)
+		}
+		filter.TagIDs = append(filter.TagIDs, uint(id))
+	}
+	switch params.Get("tag_match") {
+	case "", "any":
+	case "all":
+		filter.MatchAllTags = true
+	default:
+		return filter, fmt.Errorf("%w：tag_match 只能为 any 或 all", ErrInvalidListQuery)
+	}
+
+	filter.Author = strings.TrimSpace(params.Get("author"))
+
+	var err error
+	for key, target := range map[string]**time.Time{
+		"created_from": &filter.CreatedFrom,
+		"created_to":   &filter.CreatedTo,
+		"updated_from": &filter.UpdatedFrom,
+		"updated_to":   &filter.UpdatedTo,
+	} {
+		if *target, err = parseListTime(params.Get(key), strings.HasSuffix(key, "_to")); err != nil {
+			return filter, fmt.Errorf("%w：无效的时间 %s=%s，应为 RFC3339 或 2006-01-02 格式", ErrInvalidListQuery, key, params.Get(key))
+		}
+	}
+
+	for _, value := range splitListParam(params, "sort") {
+		key := strings.TrimPrefix(value, "-")
+		if !containsString(articleSortKeys, key) {
+			return filter, fmt.Errorf("%w：不支持的排序字段 %s，可用的排序字段：%s", ErrInvalidListQuery, key, strings.Join(articleSortKeys, ", "))
+		}
+		filter.Sort = append(filter.Sort, ArticleSort{Key: key, Desc: strings.HasPrefix(value, "-")})
+	}
+
+	for _, field := range splitListParam(params, "fields") {
+		if _, ok := articleListFields[field]; !ok {
+			names := make([]string, 0, len(articleListFields))
+			for name := range articleListFields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return filter, fmt.Errorf("%w：不支持的字段 %s，可用的字段：%s", ErrInvalidListQuery, field, strings.Join(names, ", "))
+		}
+		if !containsString(filter.Fields, field) {
+			filter.Fields = append(filter.Fields, field)
+		}
+	}
+
+	return filter, nil
+}
+
+// splitListParam 获取逗号分隔（或重复出现）的参数值，忽略空值
+func splitListParam(params url.Values, key string) []string {
+	var values []string
+	for _, value := range params[key] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// parseListTime 解析 RFC3339 或日期格式的时间，endOfDay 为 true 时只有日期的时间取当天结束
+func parseListTime(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return &t, nil
+}
+
+// containsString 检查字符串是否在列表中
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// applyArticleListFilter 按过滤条件和可见性构造文章列表查询：
+// 未发布的文章只对署名的作者和拥有审核权限的角色可见
+func applyArticleListFilter(db *gorm.DB, filter ArticleListFilter, viewerID uint, role string) (*gorm.DB, error) {
+	if !model.HasPermission(role, model.PermArticleReview) {
+		db = db.Where("articles.status = ? OR articles.user_id = ? OR articles.id IN (?)",
+			model.ArticleStatusPublished, viewerID, articleIDsCreditedTo(viewerID))
+	}
+
+	switch {
+	case filter.AnyStatus:
+	case len(filter.Statuses) > 0:
+		db = db.Where("articles.status IN ?", filter.Statuses)
+	default:
+		db = db.Where("articles.status = ?", model.ArticleStatusPublished)
+	}
+
+	if filter.CategoryID != nil {
+		db = db.Where("articles.category_id = ?", *filter.CategoryID)
+	}
+
+	if len(filter.TagIDs) > 0 {
+		tagged := config.DB.Model(&model.ArticleTag{}).Select("article_id").Where("tag_id IN ?", filter.TagIDs)
+		if filter.MatchAllTags {
+			tagged = tagged.Group("article_id").Having("COUNT(DISTINCT tag_id) = ?", len(uniqueUints(filter.TagIDs)))
+		}
+		db = db.Where("articles.id IN (?)", tagged)
+	}
+
+	if filter.Author != "" {
+		var author model.User
+		query := config.DB.Select("id")
+		if id, err := strconv.ParseUint(filter.Author, 10, 32); err == nil {
+			query = query.Where("id = ?", id)
+		} else {
+			query = query.Where("username = ?", filter.Author)
+		}
+		if err := query.Limit(1).Find(&author).Error; err != nil {
+			return nil, err
+		}
+		// 包括该用户署名的文章，作者不存在时结果为空
+		db = db.Where("articles.user_id = ? OR articles.id IN (?)", author.ID, articleIDsCreditedTo(author.ID))
+	}
+
+	if filter.CreatedFrom != nil {
+		db = db.Where("articles.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		db = db.Where("articles.created_at <= ?", *filter.CreatedTo)
+	}
+	if filter.UpdatedFrom != nil {
+		db = db.Where("articles.updated_at >= ?", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedTo != nil {
+		db = db.Where("articles.updated_at <= ?", *filter.UpdatedTo)
+	}
+	return db, nil
+}
+
+// articleKeyset 文章列表的排序方式：按排序条件排序，最后按ID倒序保证顺序稳定
+func articleKeyset(scope string, sorts []ArticleSort) keysetSpec {
+	if len(sorts) == 0 {
+		sorts = []ArticleSort{{Key: "created_at", Desc: true}}
+	}
+
+	spec := keysetSpec{Scope: scope}
+	signature := make([]string, 0, len(sorts))
+	for _, s := range sorts {
+		column := keysetColumn{Name: "articles." + s.Key, Desc: s.Desc}
+		switch s.Key {
+		case "title":
+			column.Kind = keysetString
+		case "created_at", "updated_at":
+			column.Kind = keysetTime
+		case "publish_at":
+			column.Kind = keysetTime
+			column.Nullable = true
+		default:
+			column.Kind = keysetInt
+		}
+		spec.Columns = append(spec.Columns, column)
+		if s.Desc {
+			signature = append(signature, "-"+s.Key)
+		} else {
+			signature = append(signature, s.Key)
+		}
+	}
+	spec.Columns = append(spec.Columns, keysetColumn{Name: "articles.id", Desc: true, Kind: keysetInt})
+	spec.Scope += ":" + strings.Join(signature, ",")
+
+	spec.Values = func(item interface{}) []interface{} {
+		article := item.(*model.Article)
+		values := make([]interface{}, 0, len(sorts)+1)
+		for _, s := range sorts {
+			switch s.Key {
+			case "id":
+				values = append(values, article.ID)
+			case "title":
+				values = append(values, article.Title)
+			case "created_at":
+				values = append(values, cursorTime(article.CreatedAt))
+			case "updated_at":
+				values = append(values, cursorTime(article.UpdatedAt))
+			case "publish_at":
+				values = append(values, cursorNullableTime(article.PublishAt))
+			case "view_count":
+				values = append(values, article.ViewCount)
+			case "like_count":
+				values = append(values, article.LikeCount)
+			case "comment_count":
+				values = append(values, article.CommentCount)
+			}
+		}
+		return append(values, article.ID)
+	}
+	return spec
+}
+
+// applyArticleListSelect 按返回字段设置查询的列和预加载的关联，排序字段总会被查询（用于生成游标）
+func applyArticleListSelect(db *gorm.DB, filter ArticleListFilter) *gorm.DB {
+	if len(filter.Fields) == 0 {
+		return db.Preload("User").Preload("Category").Preload("Tags").Scopes(preloadArticleAuthors)
+	}
+
+	columns := []string{"articles.id"}
+	for _, s := range filter.Sort {
+		if !containsString(columns, "articles."+s.Key) {
+			columns = append(columns, "articles."+s.Key)
+		}
+	}
+	if len(filter.Sort) == 0 {
+		columns = append(columns, "articles.created_at")
+	}
+	for _, field := range filter.Fields {
+		for _, column := range articleListFields[field] {
+			if !containsString(columns, "articles."+column) {
+				columns = append(columns, "articles."+column)
+			}
+		}
+		switch field {
+		case "user":
+			db = db.Preload("User")
+		case "category":
+			db = db.Preload("Category")
+		case "tags", "tag_ids":
+			db = db.Preload("Tags")
+		case "authors":
+			db = db.Scopes(preloadArticleAuthors)
+		}
+	}
+	return db.Select(columns)
+}
+
+// uniqueUints 去除重复的ID
+func uniqueUints(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	var result []uint
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -1,14 +1,37 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"gin-blog-system/config"
 	"gin-blog-system/model"
+	"gin-blog-system/utils"
 	"gorm.io/gorm"
+	"time"
 )
 
 // CreateArticle 创建文章
-func CreateArticle(article *model.Article) error {
+func CreateArticle(article *model.Article, actx AuditContext) error {
+	// 新文章总是从草稿开始，之后通过编辑流程提交审核和发布
+	article.Status = model.ArticleStatusDraft
+	article.PublishAt = nil
+	article.ReviewerID = 0
+	if err := validateUnpublishAt(article.UnpublishAt, nil, time.Now()); err != nil {
+		return err
+	}
+
+	// 渲染文章内容（默认按 Markdown 处理）
+	if article.ContentFormat == "" {
+		article.ContentFormat = model.ContentFormatMarkdown
+	}
+	if !model.IsValidContentFormat(article.ContentFormat) {
+		return ErrInvalidContentFormat
+	}
+	if err := renderArticle(article); err != nil {
+		return err
+	}
+
 	// 开始事务
 	tx := config.DB.Begin()
 	defer func() {
@@ -22,6 +45,14 @@ func CreateArticle(article *model.Article) error {
 		article.Cover = "/static/default_cover.png"
 	}
 
+	// 未指定 slug 时根据标题生成
+	slug, err := newSlug(tx, SlugTargetArticle, article.Slug, article.Title, 0)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	article.Slug = slug
+
 	// 创建文章
 	result := tx.Create(article)
 	if result.Error != nil {
@@ -52,46 +83,91 @@ func CreateArticle(article *model.Article) error {
 		}
 	}
 
+	if err := createPrimaryAuthor(tx, article); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordRevision(tx, article, actx.ActorID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordAudit(tx, actx, AuditArticleCreate, AuditTargetArticle, article.ID, nil, article); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// 提交事务
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	refreshSearchIndex(article.ID)
+	return nil
 }
 
 // GetArticleByID 根据ID获取文章
 func GetArticleByID(id uint) (*model.ArticleResponse, error) {
 	var article model.Article
 	// 正确的预加载方式：GORM会自动根据外键关系关联数据
-	result := config.DB.Preload("User").Preload("Category").Preload("Tags").First(&article, id)
+	result := config.DB.Preload("User").Preload("Category").Preload("Tags").Scopes(preloadArticleAuthors).First(&article, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, errors.New("文章不存在")
 	}
-	return article.ConvertToArticleResponse(), result.Error
-}
-
-// GetAllArticles 获取所有文章
-func GetAllArticles(page, pageSize int) ([]model.ArticleResponse, int64, error) {
-	var articles []model.Article
-	var total int64
+	if result.Error != nil {
+		return nil, result.Error
+	}
 
-	db := config.DB.Model(&model.Article{}).Preload("User").Preload("Category").Preload("Tags")
+	response := article.ConvertToArticleResponse()
+	if err := attachArticleSeries(response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
 
-	// 计算总数
-	db.Count(&total)
+// GetAllArticles 按过滤条件分页获取文章列表，未发布的文章只对作者本人和拥有审核权限的角色可见
+func GetAllArticles(filter ArticleListFilter, req PageRequest, viewerID uint, role string) ([]model.ArticleResponse, *PageResult, error) {
+	db, err := applyArticleListFilter(config.DB.Model(&model.Article{}), filter, viewerID, role)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// 分页查询
-	offset := (page - 1) * pageSize
-	result := db.Offset(offset).Limit(pageSize).Find(&articles)
+	var articles []model.Article
+	page, err := findPage(applyArticleListSelect(db, filter), articleKeyset("articles", filter.Sort), req, &articles)
+	if err != nil {
+		return nil, nil, err
+	}
+	return convertArticles(articles), page, nil
+}
 
-	// 转换为响应结构
-	responses := make([]model.ArticleResponse, len(articles))
+// SelectArticleFields 只保留文章响应中指定的字段，用于按需返回列表字段
+func SelectArticleFields(articles []model.ArticleResponse, fields []string) ([]map[string]interface{}, error) {
+	items := make([]map[string]interface{}, len(articles))
 	for i, article := range articles {
-		responses[i] = *article.ConvertToArticleResponse()
+		data, err := json.Marshal(article)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]interface{}
+		if err := json.Unmarshal(data, &all); err != nil {
+			return nil, err
+		}
+		items[i] = make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			items[i][field] = all[field]
+		}
 	}
+	return items, nil
+}
 
-	return responses, total, result.Error
+// UpdateArticle 更新文章，未提供的字段保持不变，标签以 articleData.Tags 为准
+// role 为操作者的角色，用于判断能否直接修改已发布的文章
+func UpdateArticle(id uint, articleData *model.Article, role string, actx AuditContext) error {
+	return updateArticle(id, articleData, role, actx, AuditArticleUpdate, nil)
 }
 
-// UpdateArticle 更新文章
-func UpdateArticle(id uint, articleData *model.Article) error {
+// updateArticle 更新文章并保存修订记录，fields 不为空时只更新指定的字段（包括零值）
+func updateArticle(id uint, articleData *model.Article, role string, actx AuditContext, action string, fields []string) error {
 	// 开始事务
 	tx := config.DB.Begin()
 	defer func() {
@@ -106,9 +182,61 @@ func UpdateArticle(id uint, articleData *model.Article) error {
 		tx.Rollback()
 		return errors.New("文章不存在")
 	}
+	before := existingArticle
+
+	// 审核中或审核通过的内容不能再修改，否则审核的将不是最终发布的内容
+	if err := checkArticleEditable(&existingArticle, role); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := validateUnpublishAt(articleData.UnpublishAt, existingArticle.PublishAt, time.Now()); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 内容或格式变化时重新渲染，缓存的 content_html 随之更新
+	if articleData.ContentFormat != "" && !model.IsValidContentFormat(articleData.ContentFormat) {
+		tx.Rollback()
+		return ErrInvalidContentFormat
+	}
+	rendered := model.Article{Content: existingArticle.Content, ContentFormat: existingArticle.ContentFormat}
+	if articleData.Content != "" || len(fields) > 0 {
+		rendered.Content = articleData.Content
+	}
+	if articleData.ContentFormat != "" {
+		rendered.ContentFormat = articleData.ContentFormat
+	}
+	if rendered.Content != existingArticle.Content || rendered.ContentFormat != existingArticle.ContentFormat ||
+		existingArticle.RenderVersion != utils.RenderVersion {
+		if err := renderArticle(&rendered); err != nil {
+			tx.Rollback()
+			return err
+		}
+		articleData.ContentHTML = rendered.ContentHTML
+		articleData.RenderVersion = rendered.RenderVersion
+	}
+
+	// 升级前创建的文章没有修订记录，先保存修改前的内容作为第一个修订
+	if err := ensureRevisionBaseline(tx, &existingArticle); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 标题或 slug 变化时更新 slug，旧 slug 保留用于跳转
+	slug, err := updateSlug(tx, SlugTargetArticle, id, existingArticle.Slug, articleData.Slug, articleData.Title, existingArticle.Title)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	articleData.Slug = slug
 
-	// 更新文章基本信息
-	result = tx.Model(&existingArticle).Updates(articleData)
+	// 更新文章基本信息（作者不随编辑者变化）
+	db := tx.Model(&existingArticle).Omit("UserID")
+	if len(fields) > 0 {
+		db = db.Select(append(fields, "slug", "content_html", "render_version"))
+	}
+	result = db.Updates(articleData)
 	if result.Error != nil {
 		tx.Rollback()
 		return result.Error
@@ -141,12 +269,26 @@ func UpdateArticle(id uint, articleData *model.Article) error {
 	// 重新加载文章以包含最新的标签信息
 	tx.Preload("Tags").First(&existingArticle, id)
 
+	if err := recordRevision(tx, &existingArticle, actx.ActorID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordAudit(tx, actx, action, AuditTargetArticle, id, before, existingArticle); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// 提交事务
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	refreshSearchIndex(id)
+	return nil
 }
 
 // DeleteArticle 删除文章
-func DeleteArticle(id uint) error {
+func DeleteArticle(id uint, actx AuditContext) error {
 	var article model.Article
 	result := config.DB.First(&article, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -159,6 +301,26 @@ func DeleteArticle(id uint) error {
 		return deleteResult.Error
 	}
 
+	// 删除文章的修订记录和审核意见
+	deleteResult = config.DB.Where("article_id = ?", id).Delete(&model.ArticleRevision{})
+	if deleteResult.Error != nil {
+		return deleteResult.Error
+	}
+	deleteResult = config.DB.Where("article_id = ?", id).Delete(&model.ReviewComment{})
+	if deleteResult.Error != nil {
+		return deleteResult.Error
+	}
+
+	// 删除署名，并将文章移出所属系列
+	deleteResult = config.DB.Where("article_id = ?", id).Delete(&model.ArticleAuthor{})
+	if deleteResult.Error != nil {
+		return deleteResult.Error
+	}
+	deleteResult = config.DB.Where("article_id = ?", id).Delete(&model.SeriesArticle{})
+	if deleteResult.Error != nil {
+		return deleteResult.Error
+	}
+
 	// 删除文章前，先删除相关的评论
 	commentDeleteResult := config.DB.Where("article_id = ?", id).Delete(&model.Comment{})
 	if commentDeleteResult.Error != nil {
@@ -167,41 +329,53 @@ func DeleteArticle(id uint) error {
 
 	// 删除文章本身
 	result = config.DB.Delete(&article)
-	return result.Error
-}
-
-// GetArticlesByCategory 根据分类获取文章
-func GetArticlesByCategory(categoryID uint, page, pageSize int) ([]model.Article, int64, error) {
-	var articles []model.Article
-	var total int64
-
-	db := config.DB.Model(&model.Article{}).Where("category_id = ? AND status = ?", categoryID, 1).Preload("User").Preload("Category").Preload("Tags")
-
-	// 计算总数
-	db.Count(&total)
+	if result.Error != nil {
+		return result.Error
+	}
 
-	// 分页查询
-	offset := (page - 1) * pageSize
-	result := db.Offset(offset).Limit(pageSize).Find(&articles)
+	if err := deleteSlugHistory(config.DB, SlugTargetArticle, id); err != nil {
+		return err
+	}
+	refreshSearchIndex(id)
 
-	return articles, total, result.Error
+	logAudit(actx, AuditArticleDelete, AuditTargetArticle, id, article, nil)
+	return nil
 }
 
-// GetArticlesByUser 根据用户获取文章
-func GetArticlesByUser(userID uint, page, pageSize int) ([]model.Article, int64, error) {
+// GetArticlesByCategory 分页获取分类下已发布的文章（按创建时间倒序）
+func GetArticlesByCategory(categoryID uint, req PageRequest) ([]model.ArticleResponse, *PageResult, error) {
 	var articles []model.Article
-	var total int64
-
-	db := config.DB.Model(&model.Article{}).Where("user_id = ? AND status = ?", userID, 1).Preload("User").Preload("Category").Preload("Tags")
+	db := publishedArticlesQuery().Where("category_id = ?", categoryID)
+	page, err := findPage(db, articleKeyset(fmt.Sprintf("category:%d", categoryID), nil), req, &articles)
+	if err != nil {
+		return nil, nil, err
+	}
+	return convertArticles(articles), page, nil
+}
 
-	// 计算总数
-	db.Count(&total)
+// publishedArticlesQuery 已发布文章的查询（预加载作者、分类、标签和署名作者）
+func publishedArticlesQuery() *gorm.DB {
+	return config.DB.Model(&model.Article{}).Where("status = ?", model.ArticleStatusPublished).Preload("User").Preload("Category").Preload("Tags").Scopes(preloadArticleAuthors)
+}
 
-	// 分页查询
-	offset := (page - 1) * pageSize
-	result := db.Offset(offset).Limit(pageSize).Find(&articles)
+// convertArticles 将文章列表转换为响应结构
+func convertArticles(articles []model.Article) []model.ArticleResponse {
+	responses := make([]model.ArticleResponse, len(articles))
+	for i, article := range articles {
+		responses[i] = *article.ConvertToArticleResponse()
+	}
+	return responses
+}
 
-	return articles, total, result.Error
+// GetArticlesByUser 分页获取用户已发布的文章（按创建时间倒序）
+func GetArticlesByUser(userID uint, req PageRequest) ([]model.ArticleResponse, *PageResult, error) {
+	var articles []model.Article
+	db := publishedArticlesQuery().Where("user_id = ?", userID)
+	page, err := findPage(db, articleKeyset(fmt.Sprintf("user:%d", userID), nil), req, &articles)
+	if err != nil {
+		return nil, nil, err
+	}
+	return convertArticles(articles), page, nil
 }
 
 // AddLike 给文章点赞（增加点赞数，检查用户是否已点赞）
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 审计操作
+const (
+	AuditArticleCreate         = "article.create"
+	AuditArticleUpdate         = "article.update"
+	AuditArticleDelete         = "article.delete"
+	AuditArticleRestore        = "article.restore"
+	AuditArticlePublish        = "article.publish"   // 调度器按计划发布
+	AuditArticleUnpublish      = "article.unpublish" // 调度器按计划下线
+	AuditArticleAssignReviewer = "article.assign_reviewer"
+	AuditArticleAuthors        = "article.authors" // 调整署名作者
+	AuditCategoryCreate        = "category.create"
+	AuditCategoryUpdate        = "category.update"
+	AuditCategoryDelete        = "category.delete"
+	AuditTagCreate             = "tag.create"
+	AuditTagUpdate             = "tag.update"
+	AuditTagDelete             = "tag.delete"
+	AuditSeriesCreate          = "series.create"
+	AuditSeriesUpdate          = "series.update"
+	AuditSeriesDelete          = "series.delete"
+	AuditSeriesArticles        = "series.articles" // 调整系列中的文章及顺序
+	AuditCommentCreate         = "comment.create"
+	AuditCommentDelete         = "comment.delete"
+	AuditUserCreate            = "user.create"
+	AuditUserUpdate            = "user.update"
+	AuditUserDelete            = "user.delete"
+	AuditUserRoleUpdate        = "user.role_update"
+	AuditUserStatusUpdate      = "user.status_update"
+	AuditLogin                 = "auth.login"
+	AuditLoginFailed           = "auth.login_failed"
+)
+
+// 审计对象类型
+const (
+	AuditTargetArticle  = "article"
+	AuditTargetCategory = "category"
+	AuditTargetTag      = "tag"
+	AuditTargetSeries   = "series"
+	AuditTargetComment  = "comment"
+	AuditTargetUser     = "user"
+)
+
+// auditIgnoredFields 不参与变更对比的字段：时间戳由数据库维护，密码等敏感字段不落入审计记录
+var auditIgnoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"password":   true,
+}
+
+// AuditContext 审计上下文：发起操作的用户和请求信息，由路由层根据请求构造
+type AuditContext struct {
+	ActorID        uint   // 操作者ID
+	ImpersonatorID uint   // 模拟登录时实际操作的管理员ID
+	IP             string // 客户端IP
+	RequestID      string // 请求ID
+}
+
+// AuditChange 单个字段的变更
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEventFilter 审计日志查询条件
+type AuditEventFilter struct {
+	ActorID    uint
+	Action     string
+	TargetType string
+	TargetID   uint
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+}
+
+// recordAudit 在指定数据库连接（或事务）中写入审计记录
+// before/after 为变更前后的对象，创建时 before 为 nil，删除时 after 为 nil
+func recordAudit(db *gorm.DB, actx AuditContext, action, targetType string, targetID uint, before, after interface{}) error {
+	changes, err := auditChanges(before, after)
+	if err != nil {
+		return err
+	}
+
+	event := model.AuditEvent{
+		ActorID:        actx.ActorID,
+		ImpersonatorID: actx.ImpersonatorID,
+		Action:         action,
+		TargetType:     targetType,
+		TargetID:       targetID,
+		Changes:        changes,
+		IP:             actx.IP,
+		RequestID:      actx.RequestID,
+	}
+	return db.Create(&event).Error
+}
+
+// logAudit 写入审计记录，用于操作本身已经完成、不在事务中的场景，写入失败只打印日志
+func logAudit(actx AuditContext, action, targetType string, targetID uint, before, after interface{}) {
+	if err := recordAudit(config.DB, actx, action, targetType, targetID, before, after); err != nil {
+		fmt.Printf("记录审计日志失败: %v\n", err)
+	}
+}
+
+// auditChanges 对比变更前后的对象，返回发生变化的字段（JSON）
+// 关联对象（嵌套的对象和数组）不参与对比，值为 null 的字段视为不存在
+func auditChanges(before, after interface{}) (string, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return "", err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return "", err
+	}
+
+	changes := make(map[string]AuditChange)
+	for key, value := range beforeFields {
+		if afterValue, ok := afterFields[key]; !ok || !reflect.DeepEqual(value, afterValue) {
+			changes[key] = AuditChange{Before: value, After: afterFields[key]}
+		}
+	}
+	for key, value := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			changes[key] = AuditChange{Before: nil, After: value}
+		}
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// auditFields 将对象转换为字段名到值的映射（字段名与JSON序列化一致）
+func auditFields(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for key, value := range fields {
+		switch value.(type) {
+		case nil, map[string]interface{}, []interface{}:
+			delete(fields, key)
+			continue
+		}
+		if auditIgnoredFields[key] {
+			delete(fields, key)
+		}
+	}
+	return fields, nil
+}
+
+// ListAuditEvents 分页查询审计日志（按时间倒序）
+func ListAuditEvents(filter AuditEventFilter, page, pageSize int) ([]model.AuditEventResponse, int64, error) {
+	var events []model.AuditEvent
+	var total int64
+
+	db := config.DB.Model(&model.AuditEvent{})
+	if filter.ActorID != 0 {
+		db = db.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		db = db.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		db = db.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		db = db.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.RequestID != "" {
+		db = db.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		db = db.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		db = db.Where("created_at < ?", *filter.To)
+	}
+
+	// 计算总数
+	db.Count(&total)
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	result := db.Order("id DESC").Offset(offset).Limit(pageSize).Find(&events)
+
+	// 转换为响应结构
+	responses := make([]model.AuditEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = *event.ConvertToAuditEventResponse()
+	}
+
+	return responses, total, result.Error
+}
@@ -2,8 +2,13 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"gin-blog-system/config"
 	"gin-blog-system/model"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,11 +16,21 @@ import (
 	"gorm.io/gorm"
 )
 
-// 定义JWT自定义声明
+// Claims JWT自定义声明，签发（GenerateToken）和校验（ParseAccessToken）共用
 type Claims struct {
+	UserID    uint       `json:"user_id"`
+	Username  string     `json:"username"`
+	Role      string     `json:"role"`
+	SessionID uint       `json:"sid"`
+	Act       *ActClaims `json:"act,omitempty"` // 模拟登录时为实际操作的管理员（RFC 8693 act 声明）
+	jwt.RegisteredClaims
+}
+
+// ActClaims 模拟登录令牌中实际操作者的信息
+type ActClaims struct {
+	Subject  string `json:"sub"`
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
-	jwt.RegisteredClaims
 }
 
 // HashPassword 对密码进行哈希加密
@@ -31,14 +46,35 @@ func CheckPasswordHash(password, hash string) bool {
 }
 
 // CreateUser 创建用户
-func CreateUser(user *model.User) error {
+func CreateUser(user *model.User, actx AuditContext) error {
+	return createUser(config.DB, user, actx)
+}
+
+// createUser 在指定数据库连接（或事务）中创建用户，自助注册时操作者记为新用户本人
+func createUser(db *gorm.DB, user *model.User, actx AuditContext) error {
+	// 校验用户名和邮箱格式
+	if err := ValidateUsername(user.Username); err != nil {
+		return err
+	}
+	if err := ValidateEmail(user.Email); err != nil {
+		return err
+	}
+
 	// 检查用户名或邮箱是否已存在
 	var existingUser model.User
-	result := config.DB.Where("username = ? OR email = ?", user.Username, user.Email).First(&existingUser)
+	result := db.Where("username = ? OR email = ?", user.Username, user.Email).First(&existingUser)
 	if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return errors.New("用户名或邮箱已存在")
 	}
 
+	// 新用户统一使用默认角色，邮箱需验证后才标记为已验证
+	user.Role = model.DefaultRole
+	user.EmailVerified = false
+	user.TwoFactorEnabled = false
+	if user.Status != model.UserStatusPending {
+		user.Status = model.UserStatusActive
+	}
+
 	// 如果没有提供头像，则设置默认头像
 	if user.Avatar == "" {
 		user.Avatar = "/static/default_avatar.png"
@@ -51,20 +87,108 @@ func CreateUser(user *model.User) error {
 	}
 	user.Password = hashedPassword
 
-	result = config.DB.Create(user)
-	return result.Error
+	result = db.Create(user)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if actx.ActorID == 0 {
+		actx.ActorID = user.ID
+	}
+	return recordAudit(db, actx, AuditUserCreate, AuditTargetUser, user.ID, nil, user)
+}
+
+// reservedUsernames 系统保留的用户名（不区分大小写），不能注册
+var reservedUsernames = map[string]bool{
+	deletedUserUsername: true,
+}
+
+// ValidateUsername 校验用户名：不能为空，不能是纯数字（/api/users/:user 中纯数字按用户ID解析），
+// 不能使用系统保留的用户名
+func ValidateUsername(username string) error {
+	if strings.TrimSpace(username) == "" {
+		return errors.New("用户名不能为空")
+	}
+	if strings.Trim(username, "0123456789") == "" {
+		return errors.New("用户名不能是纯数字")
+	}
+	if reservedUsernames[strings.ToLower(username)] {
+		return errors.New("该用户名为系统保留，不能使用")
+	}
+	return nil
 }
 
-// AuthenticateUser 用户认证
-func AuthenticateUser(username, password string) (*model.User, error) {
+// ValidateEmail 校验邮箱格式
+func ValidateEmail(email string) error {
+	if email == "" {
+		return errors.New("邮箱不能为空")
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return errors.New("邮箱格式不正确")
+	}
+	return nil
+}
+
+// ErrInvalidCredentials 用户名或密码错误（不区分用户是否存在，防止枚举用户名）
+var ErrInvalidCredentials = errors.New("用户名或密码错误")
+
+// dummyPasswordHash 用户不存在时参与比对的哈希，使响应时间与用户存在时一致
+var dummyPasswordHash, _ = HashPassword("gin-blog-system-dummy-password")
+
+// AuthenticateUser 用户认证，按账号和IP统计失败次数，超过阈值后临时锁定。
+// 启用两步验证的账号在此只校验密码，失败计数在 VerifyTwoFactorChallenge 通过后才清除
+func AuthenticateUser(username, password, userAgent string, actx AuditContext) (*model.User, error) {
+	ipKey := ipFailureKey(actx.IP)
+	if err := checkLoginLocked(ipKey); err != nil {
+		recordLoginAttempt(0, username, userAgent, actx, false, "ip_locked")
+		return nil, err
+	}
+
 	var user model.User
 	result := config.DB.Where("username = ? OR email = ?", username, username).First(&user)
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return nil, errors.New("用户不存在")
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, result.Error
+	}
+
+	accountKey := accountFailureKey(user.ID, username)
+	if err := checkLoginLocked(accountKey); err != nil {
+		recordLoginAttempt(user.ID, username, userAgent, actx, false, "account_locked")
+		return nil, err
 	}
 
-	if !CheckPasswordHash(password, user.Password) {
-		return nil, errors.New("密码错误")
+	passwordHash := user.Password
+	if user.ID == 0 {
+		passwordHash = dummyPasswordHash
+	}
+	if !CheckPasswordHash(password, passwordHash) || user.ID == 0 {
+		reason := "invalid_password"
+		if user.ID == 0 {
+			reason = "unknown_user"
+		}
+		recordLoginAttempt(user.ID, username, userAgent, actx, false, reason)
+		if err := registerLoginFailure(accountKey, ipKey); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.Status == model.UserStatusPending {
+		recordLoginAttempt(user.ID, username, userAgent, actx, false, "pending_approval")
+		return nil, ErrPendingApproval
+	}
+	if user.Status != model.UserStatusActive {
+		recordLoginAttempt(user.ID, username, userAgent, actx, false, "disabled")
+		return nil, errors.New("账号已被禁用")
+	}
+
+	// 登录成功后清除账号的失败计数（IP计数保留，避免用自己的账号重置计数）；
+	// 启用两步验证时要等第二因素通过后才算登录成功
+	if !user.TwoFactorEnabled {
+		if err := loginAttemptStore.Reset(accountKey); err != nil {
+			return nil, err
+		}
+		recordLoginAttempt(user.ID, username, userAgent, actx, true, "")
 	}
 
 	// 不返回密码字段
@@ -72,22 +196,23 @@ func AuthenticateUser(username, password string) (*model.User, error) {
 	return &user, nil
 }
 
-// GenerateToken 生成JWT令牌
-func GenerateToken(user *model.User) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour) // 令牌有效期24小时
+// GenerateToken 生成JWT访问令牌，jti 用于令牌作废，sessionID 为所属登录会话
+func GenerateToken(user *model.User, jti string, sessionID uint) (string, error) {
+	now := time.Now()
 	claims := &Claims{
-		UserID:   user.ID,
-		Username: user.Username,
+		UserID:    user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL())),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Issuer:    "gin-blog-system",
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(config.AppConfig.App.JWTSecret))
-	return tokenString, err
+	return signJWT(claims)
 }
 
 // GetUserByID 根据ID获取用户
@@ -101,12 +226,17 @@ func GetUserByID(id uint) (*model.User, error) {
 }
 
 // UpdateUser 更新用户信息
-func UpdateUser(id uint, userData *model.User) error {
+func UpdateUser(id uint, userData *model.User, actx AuditContext) error {
 	var existingUser model.User
 	result := config.DB.First(&existingUser, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return errors.New("用户不存在")
 	}
+	if userData.Username != "" && userData.Username != existingUser.Username {
+		if err := ValidateUsername(userData.Username); err != nil {
+			return err
+		}
+	}
 
 	// 检查用户名或邮箱是否已被其他用户使用
 	var checkUser model.User
@@ -115,18 +245,261 @@ func UpdateUser(id uint, userData *model.User) error {
 		return errors.New("用户名或邮箱已被其他用户使用")
 	}
 
+	before := existingUser
 	result = config.DB.Model(&existingUser).Updates(userData)
-	return result.Error
+	if result.Error != nil {
+		return result.Error
+	}
+
+	logAudit(actx, AuditUserUpdate, AuditTargetUser, id, before, existingUser)
+	return nil
+}
+
+// DeleteUser 注销用户并删除其个人数据：
+// 移除点赞并修正文章点赞数，评论转移到匿名账号，文章和系列按 articlePolicy 转移或删除，
+// 删除关注关系、登录凭据、会话、第三方绑定和上传文件，抹去审计日志中的个人信息，最后删除用户记录。
+// 一般通过 RequestUserErasure 在后台执行
+func DeleteUser(id uint, articlePolicy string, actx AuditContext) error {
+	var user model.User
+	result := config.DB.First(&user, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return errors.New("用户不存在")
+	}
+	if user.IsSystem {
+		return errors.New("匿名账号不能注销")
+	}
+
+	// 开始事务
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	ghost, err := getDeletedUserAccount(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 移除点赞，同时修正文章点赞数（每个用户对每篇文章最多一条点赞）
+	likedArticles := tx.Model(&model.Like{}).Select("article_id").Where("user_id = ?", id)
+	if err := tx.Model(&model.Article{}).Where("id IN (?) AND like_count > 0", likedArticles).
+		UpdateColumn("like_count", gorm.Expr("like_count - ?", 1)).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("user_id = ?", id).Delete(&model.Like{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 处理文章
+	var uploadPaths []string
+	var articleIDs []uint
+	if articlePolicy == model.ErasureArticleDelete {
+		if err := tx.Model(&model.Article{}).Where("user_id = ?", id).Pluck("id", &articleIDs).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		if len(articleIDs) > 0 {
+			for _, related := range []interface{}{&model.ArticleTag{}, &model.Comment{}, &model.Like{}, &model.ArticleRevision{}, &model.ReviewComment{}, &model.SeriesArticle{}, &model.ArticleAuthor{}} {
+				if err := tx.Where("article_id IN ?", articleIDs).Delete(related).Error; err != nil {
+					tx.Rollback()
+					return err
+				}
+			}
+			if err := tx.Where("id IN ?", articleIDs).Delete(&model.Article{}).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+			if err := deleteSlugHistory(tx, SlugTargetArticle, articleIDs...); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+
+		// 删除用户创建的系列（其他用户的文章移出系列后保留）
+		seriesIDs := tx.Model(&model.Series{}).Select("id").Where("user_id = ?", id)
+		if err := tx.Where("series_id IN (?)", seriesIDs).Delete(&model.SeriesArticle{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&model.Series{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		// 文章删除后上传的文件也一并删除
+		if err := tx.Model(&model.Upload{}).Where("user_id = ?", id).Pluck("path", &uploadPaths).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&model.Upload{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	} else {
+		if err := tx.Model(&model.Article{}).Where("user_id = ?", id).Update("user_id", ghost.ID).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Model(&model.Series{}).Where("user_id = ?", id).Update("user_id", ghost.ID).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Model(&model.ArticleAuthor{}).Where("user_id = ? AND role = ?", id, model.AuthorRoleAuthor).Update("user_id", ghost.ID).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		// 保留的文章可能引用上传的图片，文件随文章转移到匿名账号，只删除头像
+		avatarPath := strings.TrimPrefix(user.Avatar, "/static/")
+		if err := tx.Model(&model.Upload{}).Where("user_id = ? AND path = ?", id, avatarPath).Pluck("path", &uploadPaths).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Where("user_id = ? AND path = ?", id, avatarPath).Delete(&model.Upload{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Model(&model.Upload{}).Where("user_id = ?", id).Update("user_id", ghost.ID).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// 移除用户在他人文章上的署名
+	if err := tx.Where("user_id = ?", id).Delete(&model.ArticleAuthor{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 评论和审核意见转移到匿名账号
+	if err := tx.Model(&model.Comment{}).Where("user_id = ?", id).Update("user_id", ghost.ID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Model(&model.ReviewComment{}).Where("user_id = ?", id).Update("user_id", ghost.ID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 取消以该用户为审核人的指定
+	if err := tx.Model(&model.Article{}).Where("reviewer_id = ?", id).Update("reviewer_id", 0).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 删除关注关系
+	if err := tx.Where("follower_id = ? OR followee_id = ?", id, id).Delete(&model.Follow{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 删除登录凭据、会话等账号数据
+	for _, related := range []interface{}{
+		&model.RefreshToken{},
+		&model.Session{},
+		&model.UserToken{},
+		&model.PersonalAccessToken{},
+		&model.UserIdentity{},
+		&model.OAuthState{},
+		&model.LoginAttempt{},
+		&model.TagFollow{},
+	} {
+		if err := tx.Where("user_id = ?", id).Delete(related).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// 审计日志保留操作记录（用户ID注销后不再对应任何人），但清空针对该用户的变更内容（用户名、邮箱等）
+	// 以及该用户发起或针对该用户的事件中的IP
+	if err := tx.Model(&model.AuditEvent{}).Where("target_type = ? AND target_id = ?", AuditTargetUser, id).
+		Update("changes", "{}").Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Model(&model.AuditEvent{}).Where("actor_id = ? OR (target_type = ? AND target_id = ?)", id, AuditTargetUser, id).
+		Update("ip", "").Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&user).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 提交事务（注销事件只记录用户ID）
+	if err := recordAudit(tx, actx, AuditUserDelete, AuditTargetUser, id, map[string]interface{}{"id": id}, nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	if err := loginAttemptStore.Reset(accountFailureKey(id, "")); err != nil {
+		fmt.Printf("清理登录失败计数失败: %v\n", err)
+	}
+	refreshSearchIndex(articleIDs...)
+	for _, uploadPath := range uploadPaths {
+		if err := os.Remove(filepath.Join(config.AppConfig.Upload.SavePath, uploadPath)); err != nil && !os.IsNotExist(err) {
+			fmt.Printf("删除上传文件失败: %v\n", err)
+		}
+	}
+	return nil
 }
 
-// DeleteUser 删除用户
-func DeleteUser(id uint) error {
+// UpdateUserRole 修改用户角色，并作废其全部令牌使旧角色立即失效
+func UpdateUserRole(id uint, role string, actx AuditContext) error {
+	if !model.IsValidRole(role) {
+		return errors.New("无效的角色")
+	}
+
 	var user model.User
 	result := config.DB.First(&user, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return errors.New("用户不存在")
 	}
 
-	result = config.DB.Delete(&user)
-	return result.Error
+	before := user
+	result = config.DB.Model(&user).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	logAudit(actx, AuditUserRoleUpdate, AuditTargetUser, id, before, user)
+
+	// 令牌中的 role 声明已经过期，作废后用户需要重新登录以取得新角色
+	return RevokeAllUserTokens(id, "role_changed")
+}
+
+// UpdateUserStatus 修改用户状态，禁用用户时立即作废其全部令牌
+func UpdateUserStatus(id uint, status int, actx AuditContext) error {
+	if status != model.UserStatusDisabled && status != model.UserStatusActive {
+		return errors.New("无效的用户状态")
+	}
+
+	var user model.User
+	result := config.DB.First(&user, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return errors.New("用户不存在")
+	}
+
+	before := user
+	result = config.DB.Model(&user).Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	logAudit(actx, AuditUserStatusUpdate, AuditTargetUser, id, before, user)
+
+	if status == model.UserStatusDisabled {
+		return RevokeAllUserTokens(id, "user_banned")
+	}
+	return nil
 }
@@ -0,0 +1,26 @@
+package service
+
+import "testing"
+
+func TestValidateUsername(t *testing.T) {
+	tests := []struct {
+		username string
+		wantErr  bool
+	}{
+		{"alice", false},
+		{"user42", false},
+		{"42user", false},
+		{"deleted_user_ab12", false},
+		{"", true},
+		{"   ", true},
+		{"42", true},
+		{"0007", true},
+		{"deleted_user", true},
+		{"Deleted_User", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateUsername(tt.username); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateUsername(%q) error = %v, wantErr %v", tt.username, err, tt.wantErr)
+		}
+	}
+}
@@ -8,15 +8,27 @@ import (
 )
 
 // CreateCategory 创建分类
-func CreateCategory(category *model.Category) error {
+func CreateCategory(category *model.Category, actx AuditContext) error {
+	// 未指定 slug 时根据名称生成
+	slug, err := newSlug(config.DB, SlugTargetCategory, category.Slug, category.Name, 0)
+	if err != nil {
+		return err
+	}
+	category.Slug = slug
+
 	result := config.DB.Create(category)
-	return result.Error
+	if result.Error != nil {
+		return result.Error
+	}
+
+	logAudit(actx, AuditCategoryCreate, AuditTargetCategory, category.ID, nil, category)
+	return nil
 }
 
-// GetCategoryByID 根据ID获取分类
+// GetCategoryByID 根据ID获取分类（只返回已发布的文章）
 func GetCategoryByID(id uint) (*model.Category, error) {
 	var category model.Category
-	result := config.DB.Preload("Articles").First(&category, id)
+	result := config.DB.Preload("Articles", "status = ?", model.ArticleStatusPublished).First(&category, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, errors.New("分类不存在")
 	}
@@ -26,24 +38,47 @@ func GetCategoryByID(id uint) (*model.Category, error) {
 // GetAllCategories 获取所有分类
 func GetAllCategories() ([]model.Category, error) {
 	var categories []model.Category
-	result := config.DB.Preload("Articles").Find(&categories)
+	result := config.DB.Preload("Articles", "status = ?", model.ArticleStatusPublished).Find(&categories)
 	return categories, result.Error
 }
 
 // UpdateCategory 更新分类
-func UpdateCategory(id uint, categoryData *model.Category) error {
+func UpdateCategory(id uint, categoryData *model.Category, actx AuditContext) error {
 	var existingCategory model.Category
 	result := config.DB.First(&existingCategory, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return errors.New("分类不存在")
 	}
 
+	before := existingCategory
+
+	// 名称或 slug 变化时更新 slug，旧 slug 保留用于跳转
+	slug, err := updateSlug(config.DB, SlugTargetCategory, id, existingCategory.Slug, categoryData.Slug, categoryData.Name, existingCategory.Name)
+	if err != nil {
+		return err
+	}
+	categoryData.Slug = slug
+
 	result = config.DB.Model(&existingCategory).Updates(categoryData)
-	return result.Error
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// 分类名称写入了搜索文档，需要更新该分类下的文章
+	if existingCategory.Name != before.Name {
+		var articleIDs []uint
+		if err := config.DB.Model(&model.Article{}).Where("category_id = ?", id).Pluck("id", &articleIDs).Error; err != nil {
+			return err
+		}
+		refreshSearchIndex(articleIDs...)
+	}
+
+	logAudit(actx, AuditCategoryUpdate, AuditTargetCategory, id, before, existingCategory)
+	return nil
 }
 
 // DeleteCategory 删除分类
-func DeleteCategory(id uint) error {
+func DeleteCategory(id uint, actx AuditContext) error {
 	var category model.Category
 	result := config.DB.First(&category, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -51,18 +86,32 @@ func DeleteCategory(id uint) error {
 	}
 
 	// 删除分类前，需要处理相关的文章（可以将文章的分类设为NULL或其他默认分类）
+	var articleIDs []uint
+	if err := config.DB.Model(&model.Article{}).Where("category_id = ?", id).Pluck("id", &articleIDs).Error; err != nil {
+		return err
+	}
 	result = config.DB.Model(&model.Article{}).Where("category_id = ?", id).Update("category_id", 0)
 	if result.Error != nil {
 		return result.Error
 	}
+	refreshSearchIndex(articleIDs...)
 
 	result = config.DB.Delete(&category)
-	return result.Error
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if err := deleteSlugHistory(config.DB, SlugTargetCategory, id); err != nil {
+		return err
+	}
+
+	logAudit(actx, AuditCategoryDelete, AuditTargetCategory, id, category, nil)
+	return nil
 }
 
 // GetCategoriesWithStatus 根据状态获取分类
 func GetCategoriesWithStatus(status int) ([]model.Category, error) {
 	var categories []model.Category
-	result := config.DB.Where("status = ?", status).Preload("Articles").Find(&categories)
+	result := config.DB.Where("status = ?", status).Preload("Articles", "status = ?", model.ArticleStatusPublished).Find(&categories)
 	return categories, result.Error
 }
@@ -2,13 +2,14 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"gin-blog-system/config"
 	"gin-blog-system/model"
 	"gorm.io/gorm"
 )
 
 // CreateComment 创建评论
-func CreateComment(comment *model.Comment) error {
+func CreateComment(comment *model.Comment, actx AuditContext) error {
 	// 验证文章是否存在
 	var article model.Article
 	result := config.DB.First(&article, comment.ArticleID)
@@ -47,6 +48,11 @@ func CreateComment(comment *model.Comment) error {
 		return updateResult.Error
 	}
 
+	if err := recordAudit(tx, actx, AuditCommentCreate, AuditTargetComment, comment.ID, nil, comment); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// 提交事务
 	if err := tx.Commit().Error; err != nil {
 		return err
@@ -64,10 +70,9 @@ func CreateComment(comment *model.Comment) error {
 	return nil
 }
 
-// GetCommentsByArticle 获取文章评论列表（支持分页）
-func GetCommentsByArticle(articleID uint, page, pageSize int) ([]model.CommentResponse, int64, error) {
+// GetCommentsByArticle 获取文章评论列表（支持页码分页和游标分页，按发表时间正序）
+func GetCommentsByArticle(articleID uint, req PageRequest) ([]model.CommentResponse, *PageResult, error) {
 	var comments []model.Comment
-	var total int64
 
 	db := config.DB.Model(&model.Comment{}).
 		Where("article_id = ? AND status = ?", articleID, 1).
@@ -75,12 +80,21 @@ func GetCommentsByArticle(articleID uint, page, pageSize int) ([]model.CommentRe
 		Preload("Parent").
 		Preload("Parent.User")
 
-	// 计算总数
-	db.Count(&total)
-
-	// 分页查询
-	offset := (page - 1) * pageSize
-	result := db.Offset(offset).Limit(pageSize).Find(&comments)
+	spec := keysetSpec{
+		Scope: fmt.Sprintf("comments:%d", articleID),
+		Columns: []keysetColumn{
+			{Name: "comments.created_at", Kind: keysetTime},
+			{Name: "comments.id", Kind: keysetInt},
+		},
+		Values: func(item interface{}) []interface{} {
+			comment := item.(*model.Comment)
+			return []interface{}{cursorTime(comment.CreatedAt), comment.ID}
+		},
+	}
+	page, err := findPage(db, spec, req, &comments)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// 转换为响应结构
 	responses := make([]model.CommentResponse, len(comments))
@@ -88,11 +102,11 @@ func GetCommentsByArticle(articleID uint, page, pageSize int) ([]model.CommentRe
 		responses[i] = *comment.ConvertToCommentResponse()
 	}
 
-	return responses, total, result.Error
+	return responses, page, nil
 }
 
 // DeleteComment 删除评论（级联删除子评论）
-func DeleteComment(id uint) error {
+func DeleteComment(id uint, actx AuditContext) error {
 	var comment model.Comment
 	result := config.DB.First(&comment, id)
 	if result.Error != nil {
@@ -129,6 +143,11 @@ func DeleteComment(id uint) error {
 		return updateResult.Error
 	}
 
+	if err := recordAudit(tx, actx, AuditCommentDelete, AuditTargetComment, id, comment, nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	return tx.Commit().Error
 }
 
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+)
+
+// GetFeed 获取用户的个性化信息流：关注的作者和关注的标签下已发布的文章，按发布时间倒序
+// cursor 为空时从最新的文章开始，返回下一页的游标，没有更多文章时为空
+func GetFeed(userID uint, cursor string, limit int) ([]model.ArticleResponse, string, error) {
+	followedAuthors := config.DB.Model(&model.Follow{}).Select("followee_id").Where("follower_id = ?", userID)
+	followedTags := config.DB.Model(&model.TagFollow{}).Select("tag_id").Where("user_id = ?", userID)
+	taggedArticles := config.DB.Model(&model.ArticleTag{}).Select("article_id").Where("tag_id IN (?)", followedTags)
+
+	db := publishedArticlesQuery().
+		Where("user_id <> ?", userID).
+		Where(config.DB.Where("user_id IN (?)", followedAuthors).Or("id IN (?)", taggedArticles))
+
+	var articles []model.Article
+	page, err := findPage(db, articleKeyset(fmt.Sprintf("feed:%d", userID), nil), PageRequest{PageSize: limit, Cursor: cursor, Page: 1}, &articles)
+	if err != nil {
+		return nil, "", err
+	}
+	return convertArticles(articles), page.NextCursor, nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FollowUser 关注用户，重复关注不报错
+func FollowUser(followerID, followeeID uint) error {
+	if followerID == followeeID {
+		return errors.New("不能关注自己")
+	}
+
+	followee, err := GetUserByID(followeeID)
+	if err != nil {
+		return err
+	}
+	if followee.Status != model.UserStatusActive {
+		return errors.New("用户不存在")
+	}
+
+	follow := model.Follow{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	}
+	result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow)
+	return result.Error
+}
+
+// UnfollowUser 取消关注用户
+func UnfollowUser(followerID, followeeID uint) error {
+	result := config.DB.Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&model.Follow{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("未关注该用户")
+	}
+	return nil
+}
+
+// IsFollowing 检查是否已关注用户
+func IsFollowing(followerID, followeeID uint) (bool, error) {
+	var count int64
+	result := config.DB.Model(&model.Follow{}).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Count(&count)
+	return count > 0, result.Error
+}
+
+// GetFollowers 获取用户的粉丝列表（按关注时间倒序）
+func GetFollowers(userID uint, page, pageSize int) ([]model.PublicUserResponse, int64, error) {
+	return listFollowUsers("follower_id", "followee_id = ?", userID, page, pageSize)
+}
+
+// GetFollowing 获取用户关注的人（按关注时间倒序）
+func GetFollowing(userID uint, page, pageSize int) ([]model.PublicUserResponse, int64, error) {
+	return listFollowUsers("followee_id", "follower_id = ?", userID, page, pageSize)
+}
+
+// listFollowUsers 分页查询关注关系另一端的用户，userColumn 为另一端用户ID所在的列
+func listFollowUsers(userColumn, condition string, userID uint, page, pageSize int) ([]model.PublicUserResponse, int64, error) {
+	var ids []uint
+	var total int64
+
+	db := config.DB.Model(&model.Follow{}).Where(condition, userID)
+
+	// 计算总数
+	db.Count(&total)
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	if err := db.Order("id DESC").Offset(offset).Limit(pageSize).Pluck(userColumn, &ids).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []model.User
+	if len(ids) > 0 {
+		if err := config.DB.Where("id IN ?", ids).Find(&users).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+	usersByID := make(map[uint]model.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	// 保持关注时间顺序
+	responses := make([]model.PublicUserResponse, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := usersByID[id]; ok {
+			responses = append(responses, *user.ConvertToPublicUserResponse())
+		}
+	}
+	return responses, total, nil
+}
+
+// GetFollowCounts 获取用户的粉丝数和关注数
+func GetFollowCounts(userID uint) (followers int64, following int64, err error) {
+	if err = config.DB.Model(&model.Follow{}).Where("followee_id = ?", userID).Count(&followers).Error; err != nil {
+		return 0, 0, err
+	}
+	if err = config.DB.Model(&model.Follow{}).Where("follower_id = ?", userID).Count(&following).Error; err != nil {
+		return 0, 0, err
+	}
+	return followers, following, nil
+}
+
+// FollowTag 关注标签，重复关注不报错
+func FollowTag(userID, tagID uint) error {
+	var tag model.Tag
+	result := config.DB.First(&tag, tagID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return errors.New("标签不存在")
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+
+	follow := model.TagFollow{
+		UserID: userID,
+		TagID:  tagID,
+	}
+	return config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow).Error
+}
+
+// UnfollowTag 取消关注标签
+func UnfollowTag(userID, tagID uint) error {
+	result := config.DB.Where("user_id = ? AND tag_id = ?", userID, tagID).Delete(&model.TagFollow{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("未关注该标签")
+	}
+	return nil
+}
+
+// GetFollowedTags 获取用户关注的标签
+func GetFollowedTags(userID uint) ([]model.TagResponse, error) {
+	var tags []model.Tag
+	result := config.DB.Where("id IN (?)", config.DB.Model(&model.TagFollow{}).Select("tag_id").Where("user_id = ?", userID)).
+		Order("name").Find(&tags)
+
+	// 转换为响应结构
+	responses := make([]model.TagResponse, len(tags))
+	for i, tag := range tags {
+		responses[i] = model.TagResponse{
+			ID:        tag.ID,
+			Name:      tag.Name,
+			Slug:      tag.Slug,
+			Color:     tag.Color,
+			Status:    tag.Status,
+			CreatedAt: utils.CustomTime{Time: tag.CreatedAt},
+			UpdatedAt: utils.CustomTime{Time: tag.UpdatedAt},
+		}
+	}
+	return responses, result.Error
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// 模拟登录动作
+const (
+	ImpersonationActionStart   = "start"
+	ImpersonationActionRequest = "request"
+	ImpersonationActionRevoke  = "revoke"
+)
+
+// impersonationTokenTTL 模拟令牌的最长有效期，短于普通访问令牌
+const impersonationTokenTTL = 5 * time.Minute
+
+// ErrImpersonationNotFound 模拟令牌不存在
+var ErrImpersonationNotFound = errors.New("模拟令牌不存在")
+
+// ImpersonationToken 模拟登录令牌
+type ImpersonationToken struct {
+	AccessToken string              `json:"token"`
+	JTI         string              `json:"jti"`        // 令牌ID，用于提前作废
+	ExpiresIn   int64               `json:"expires_in"` // 有效期（秒）
+	User        *model.UserResponse `json:"user"`       // 被模拟的用户
+}
+
+// ImpersonationEventFilter 模拟登录审计记录查询条件
+type ImpersonationEventFilter struct {
+	AdminID uint
+	UserID  uint
+}
+
+// IssueImpersonationToken 管理员签发模拟指定用户的访问令牌
+// 令牌带有 act 声明标明实际操作的管理员，不签发刷新令牌，过期后需重新签发
+func IssueImpersonationToken(adminID, targetID uint, clientIP string) (*ImpersonationToken, error) {
+	if adminID == targetID {
+		return nil, errors.New("不能模拟自己")
+	}
+
+	admin, err := GetUserByID(adminID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := GetUserByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Role == model.RoleAdmin {
+		return nil, errors.New("不能模拟管理员账号")
+	}
+	if target.Status != model.UserStatusActive {
+		return nil, errors.New("只能模拟状态正常的用户")
+	}
+
+	jti, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ttl := impersonationTokenTTL
+	if accessTTL := accessTokenTTL(); accessTTL < ttl {
+		ttl = accessTTL
+	}
+	claims := &Claims{
+		UserID:   target.ID,
+		Username: target.Username,
+		Role:     target.Role,
+		Act: &ActClaims{
+			Subject:  strconv.FormatUint(uint64(admin.ID), 10),
+			UserID:   admin.ID,
+			Username: admin.Username,
+		},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "gin-blog-system",
+		},
+	}
+	token, err := signJWT(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	event := model.ImpersonationEvent{
+		AdminID: admin.ID,
+		UserID:  target.ID,
+		JTI:     jti,
+		Action:  ImpersonationActionStart,
+		IP:      clientIP,
+	}
+	if err := config.DB.Create(&event).Error; err != nil {
+		return nil, err
+	}
+
+	return &ImpersonationToken{
+		AccessToken: token,
+		JTI:         jti,
+		ExpiresIn:   int64(ttl.Seconds()),
+		User:        target.ConvertToUserResponse(),
+	}, nil
+}
+
+// RevokeImpersonationToken 作废模拟令牌，adminID 为执行作废的管理员
+func RevokeImpersonationToken(jti string, adminID uint, clientIP string) error {
+	var start model.ImpersonationEvent
+	result := config.DB.Where("jti = ? AND action = ?", jti, ImpersonationActionStart).First(&start)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return ErrImpersonationNotFound
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// 模拟令牌的有效期不超过普通访问令牌，按较长者保留作废记录
+	if err := RevokeAccessToken(jti, start.UserID, start.CreatedAt.Add(accessTokenTTL()), "impersonation_revoked"); err != nil {
+		return err
+	}
+
+	event := model.ImpersonationEvent{
+		AdminID: adminID,
+		UserID:  start.UserID,
+		JTI:     jti,
+		Action:  ImpersonationActionRevoke,
+		IP:      clientIP,
+	}
+	return config.DB.Create(&event).Error
+}
+
+// CheckImpersonator 检查签发模拟令牌的管理员是否仍为状态正常的管理员
+func CheckImpersonator(adminID uint) error {
+	var admin model.User
+	result := config.DB.Select("id", "role", "status").First(&admin, adminID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return errors.New("模拟登录的管理员不存在")
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if admin.Role != model.RoleAdmin || admin.Status != model.UserStatusActive {
+		return errors.New("模拟登录的管理员已失去管理员权限")
+	}
+	return nil
+}
+
+// RecordImpersonatedRequest 记录使用模拟令牌发起的请求
+func RecordImpersonatedRequest(adminID, userID uint, jti, method, path string, status int, clientIP string) {
+	event := model.ImpersonationEvent{
+		AdminID: adminID,
+		UserID:  userID,
+		JTI:     jti,
+		Action:  ImpersonationActionRequest,
+		Method:  method,
+		Path:    truncateString(path, 255),
+		Status:  status,
+		IP:      clientIP,
+	}
+	if err := config.DB.Create(&event).Error; err != nil {
+		fmt.Printf("记录模拟登录请求失败: %v\n", err)
+	}
+}
+
+// ListImpersonationEvents 分页查询模拟登录审计记录
+func ListImpersonationEvents(filter ImpersonationEventFilter, page, pageSize int) ([]model.ImpersonationEvent, int64, error) {
+	var events []model.ImpersonationEvent
+	var total int64
+
+	db := config.DB.Model(&model.ImpersonationEvent{})
+	if filter.AdminID != 0 {
+		db = db.Where("admin_id = ?", filter.AdminID)
+	}
+	if filter.UserID != 0 {
+		db = db.Where("user_id = ?", filter.UserID)
+	}
+
+	// 计算总数
+	db.Count(&total)
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	result := db.Order("id DESC").Offset(offset).Limit(pageSize).Find(&events)
+
+	return events, total, result.Error
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/utils"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtKey JWT签名/验签密钥
+type jwtKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer // 仅用于验签的密钥为nil
+	public  crypto.PublicKey
+}
+
+var (
+	// jwtKeys 全部验签密钥（按 kid 索引），启动时加载后只读
+	jwtKeys = make(map[string]*jwtKey)
+	// jwtSigningKey 当前签名密钥，为nil时使用 jwt_secret 进行 HS256 签名
+	jwtSigningKey *jwtKey
+)
+
+// InitJWTKeys 加载JWT签名和验签密钥
+func InitJWTKeys() error {
+	conf := config.AppConfig.JWT
+	keys := make(map[string]*jwtKey, len(conf.Keys))
+
+	for _, keyConf := range conf.Keys {
+		if keyConf.Kid == "" {
+			return errors.New("JWT密钥缺少 kid")
+		}
+		if _, ok := keys[keyConf.Kid]; ok {
+			return fmt.Errorf("JWT密钥 kid 重复: %s", keyConf.Kid)
+		}
+
+		key, err := loadJWTKey(keyConf)
+		if err != nil {
+			return fmt.Errorf("加载JWT密钥 %s 失败: %w", keyConf.Kid, err)
+		}
+		keys[keyConf.Kid] = key
+	}
+
+	var signingKey *jwtKey
+	if conf.SigningKeyID != "" {
+		key, ok := keys[conf.SigningKeyID]
+		if !ok {
+			return fmt.Errorf("未找到签名密钥: %s", conf.SigningKeyID)
+		}
+		if key.private == nil {
+			return fmt.Errorf("签名密钥 %s 未配置私钥", conf.SigningKeyID)
+		}
+		signingKey = key
+	} else if len(keys) > 0 {
+		return errors.New("配置了JWT密钥时必须指定 signing_key_id")
+	}
+
+	if signingKey == nil && config.AppConfig.App.JWTSecret == "" {
+		return errors.New("未配置 jwt_secret 或JWT签名密钥")
+	}
+	// 空密钥的 HS256 令牌任何人都可以伪造
+	if conf.AcceptHS256 && config.AppConfig.App.JWTSecret == "" {
+		return errors.New("启用 accept_hs256 时必须配置 jwt_secret")
+	}
+
+	jwtKeys = keys
+	jwtSigningKey = signingKey
+	if signingKey != nil {
+		fmt.Printf("JWT使用 %s 签名，kid=%s，验签密钥数=%d\n", signingKey.method.Alg(), signingKey.kid, len(keys))
+	}
+	return nil
+}
+
+// loadJWTKey 从PEM文件加载密钥并确定签名算法
+func loadJWTKey(conf config.JWTKeyConf) (*jwtKey, error) {
+	key := &jwtKey{kid: conf.Kid}
+
+	if conf.PrivateKeyFile != "" {
+		data, err := os.ReadFile(conf.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key.private, err = utils.ParsePrivateKeyPEM(data)
+		if err != nil {
+			return nil, err
+		}
+		key.public = key.private.Public()
+	} else if conf.PublicKeyFile != "" {
+		data, err := os.ReadFile(conf.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key.public, err = utils.ParsePublicKeyPEM(data)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, errors.New("未配置 private_key_file 或 public_key_file")
+	}
+
+	method, err := jwtSigningMethod(conf.Algorithm, key.public)
+	if err != nil {
+		return nil, err
+	}
+	key.method = method
+	return key, nil
+}
+
+// jwtSigningMethod 根据配置的算法和密钥类型确定签名算法，不允许使用对称算法
+func jwtSigningMethod(alg string, pub crypto.PublicKey) (jwt.SigningMethod, error) {
+	if alg == "" {
+		switch k := pub.(type) {
+		case *rsa.PublicKey:
+			alg = "RS256"
+		case *ecdsa.PublicKey:
+			switch k.Curve.Params().BitSize {
+			case 256:
+				alg = "ES256"
+			case 384:
+				alg = "ES384"
+			case 521:
+				alg = "ES512"
+			}
+		case ed25519.PublicKey:
+			alg = "EdDSA"
+		}
+	}
+
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return nil, fmt.Errorf("不支持的签名算法: %s", alg)
+	}
+
+	var ok bool
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, ok = pub.(*rsa.PublicKey)
+	case *jwt.SigningMethodECDSA:
+		_, ok = pub.(*ecdsa.PublicKey)
+	case *jwt.SigningMethodEd25519:
+		_, ok = pub.(ed25519.PublicKey)
+	}
+	if !ok {
+		return nil, fmt.Errorf("签名算法 %s 与密钥类型不匹配", alg)
+	}
+	return method, nil
+}
+
+// signJWT 使用当前签名密钥签发JWT
+func signJWT(claims jwt.Claims) (string, error) {
+	if jwtSigningKey == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(config.AppConfig.App.JWTSecret))
+	}
+
+	token := jwt.NewWithClaims(jwtSigningKey.method, claims)
+	token.Header["kid"] = jwtSigningKey.kid
+	return token.SignedString(jwtSigningKey.private)
+}
+
+// hs256Enabled 是否接受 jwt_secret 签发的 HS256 令牌
+func hs256Enabled() bool {
+	return jwtSigningKey == nil || config.AppConfig.JWT.AcceptHS256
+}
+
+// jwtValidMethods 当前接受的签名算法
+func jwtValidMethods() []string {
+	var methods []string
+	seen := make(map[string]bool)
+	if hs256Enabled() {
+		methods = append(methods, "HS256")
+		seen["HS256"] = true
+	}
+	for _, key := range jwtKeys {
+		alg := key.method.Alg()
+		if !seen[alg] {
+			methods = append(methods, alg)
+			seen[alg] = true
+		}
+	}
+	return methods
+}
+
+// jwtVerificationKey 根据令牌头部的 kid 选择验签密钥，并校验算法与密钥匹配
+func jwtVerificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if token.Method.Alg() == "HS256" && hs256Enabled() {
+			if config.AppConfig.App.JWTSecret == "" {
+				return nil, errors.New("未配置 jwt_secret")
+			}
+			return []byte(config.AppConfig.App.JWTSecret), nil
+		}
+		return nil, errors.New("令牌缺少 kid")
+	}
+
+	key, ok := jwtKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("未知的签名密钥: %s", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("签名算法与密钥不匹配")
+	}
+	return key.public, nil
+}
+
+// ParseAccessToken 解析并校验JWT访问令牌（签名和有效期）
+func ParseAccessToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, jwtVerificationKey, jwt.WithValidMethods(jwtValidMethods()))
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("token is invalid or expired")
+	}
+	return claims, nil
+}
+
+// PublicJWKS 获取全部验签公钥，供其他服务校验本服务签发的令牌
+func PublicJWKS() (*utils.JWKSet, error) {
+	set := &utils.JWKSet{Keys: make([]utils.JWK, 0, len(jwtKeys))}
+	for _, key := range jwtKeys {
+		jwk, err := utils.NewJWK(key.kid, key.method.Alg(), key.public)
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+	return set, nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"gin-blog-system/config"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writeTestSigningKey 生成 Ed25519 私钥并写入临时PEM文件
+func writeTestSigningKey(t *testing.T) string {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestInitJWTKeysHS256Secret(t *testing.T) {
+	keyFile := writeTestSigningKey(t)
+	defer func(conf *config.AppConf) { config.AppConfig = conf; jwtKeys, jwtSigningKey = nil, nil }(config.AppConfig)
+
+	tests := []struct {
+		name        string
+		secret      string
+		signingKey  bool
+		acceptHS256 bool
+		wantErr     bool
+	}{
+		{name: "仅 jwt_secret", secret: "secret"},
+		{name: "未配置任何密钥", wantErr: true},
+		{name: "非对称密钥", signingKey: true},
+		{name: "迁移期间保留 jwt_secret", secret: "secret", signingKey: true, acceptHS256: true},
+		{name: "接受 HS256 但 jwt_secret 为空", signingKey: true, acceptHS256: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig = &config.AppConf{}
+			config.AppConfig.App.JWTSecret = tt.secret
+			config.AppConfig.JWT.AcceptHS256 = tt.acceptHS256
+			if tt.signingKey {
+				config.AppConfig.JWT.SigningKeyID = "k1"
+				config.AppConfig.JWT.Keys = []config.JWTKeyConf{{Kid: "k1", PrivateKeyFile: keyFile}}
+			}
+
+			err := InitJWTKeys()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("InitJWTKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJWTVerificationKeyRejectsEmptySecret(t *testing.T) {
+	defer func(conf *config.AppConf) { config.AppConfig = conf; jwtKeys, jwtSigningKey = nil, nil }(config.AppConfig)
+	config.AppConfig = &config.AppConf{}
+	jwtKeys, jwtSigningKey = nil, nil
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserID: 1, Role: "admin"})
+	signed, err := token.SignedString([]byte(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseAccessToken(signed); err == nil {
+		t.Fatal("空 jwt_secret 签名的令牌不应通过校验")
+	}
+}
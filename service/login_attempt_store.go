@@ -0,0 +1,208 @@
+package service
+
+import (
+	"errors"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginFailureState 登录失败状态
+type LoginFailureState struct {
+	Failures     int       // 连续失败次数
+	LastFailedAt time.Time // 最近一次失败时间
+	LockedUntil  time.Time // 锁定截止时间，零值表示未锁定
+}
+
+// LoginAttemptStore 登录失败计数存储
+type LoginAttemptStore interface {
+	// Get 获取失败状态，不存在时返回零值
+	Get(key string) (LoginFailureState, error)
+	// Increment 失败次数加一，距上次失败超过 window 时重新计数，返回更新后的状态
+	Increment(key string, window time.Duration) (LoginFailureState, error)
+	// Lock 设置锁定截止时间
+	Lock(key string, until time.Time) error
+	// Reset 清除失败状态
+	Reset(key string) error
+	// Purge 清理 before 之前的失败记录（已锁定且未到期的记录除外）
+	Purge(before time.Time) error
+}
+
+// MemoryLoginAttemptStore 基于内存的失败计数存储，适用于单实例部署和测试
+type MemoryLoginAttemptStore struct {
+	states map[string]LoginFailureState
+	mutex  sync.Mutex
+}
+
+// NewMemoryLoginAttemptStore 创建内存失败计数存储
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{
+		states: make(map[string]LoginFailureState),
+	}
+}
+
+// Get 获取失败状态
+func (s *MemoryLoginAttemptStore) Get(key string) (LoginFailureState, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.states[key], nil
+}
+
+// Increment 失败次数加一
+func (s *MemoryLoginAttemptStore) Increment(key string, window time.Duration) (LoginFailureState, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	state := s.states[key]
+	if now.Sub(state.LastFailedAt) > window && now.After(state.LockedUntil) {
+		state = LoginFailureState{}
+	}
+	state.Failures++
+	state.LastFailedAt = now
+	s.states[key] = state
+	return state, nil
+}
+
+// Lock 设置锁定截止时间
+func (s *MemoryLoginAttemptStore) Lock(key string, until time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state := s.states[key]
+	state.LockedUntil = until
+	s.states[key] = state
+	return nil
+}
+
+// Reset 清除失败状态
+func (s *MemoryLoginAttemptStore) Reset(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.states, key)
+	return nil
+}
+
+// Purge 清理过期的失败记录
+func (s *MemoryLoginAttemptStore) Purge(before time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for key, state := range s.states {
+		if state.LastFailedAt.Before(before) && now.After(state.LockedUntil) {
+			delete(s.states, key)
+		}
+	}
+	return nil
+}
+
+// DBLoginAttemptStore 基于数据库的失败计数存储，适用于多实例部署
+type DBLoginAttemptStore struct {
+	db *gorm.DB
+}
+
+// NewDBLoginAttemptStore 创建数据库失败计数存储
+func NewDBLoginAttemptStore(db *gorm.DB) *DBLoginAttemptStore {
+	return &DBLoginAttemptStore{db: db}
+}
+
+// Get 获取失败状态
+func (s *DBLoginAttemptStore) Get(key string) (LoginFailureState, error) {
+	var record model.LoginFailure
+	result := s.db.Where("counter_key = ?", key).First(&record)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return LoginFailureState{}, nil
+	}
+	if result.Error != nil {
+		return LoginFailureState{}, result.Error
+	}
+	return loginFailureToState(record), nil
+}
+
+// Increment 失败次数加一（行锁保证并发安全）
+func (s *DBLoginAttemptStore) Increment(key string, window time.Duration) (LoginFailureState, error) {
+	var state LoginFailureState
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var record model.LoginFailure
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("counter_key = ?", key).Limit(1).Find(&record)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			record = model.LoginFailure{Key: key, Failures: 1, LastFailedAt: now}
+			// 并发创建同一个键时以先创建的为准
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "counter_key"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"failures": gorm.Expr("failures + 1"), "last_failed_at": now}),
+			}).Create(&record).Error; err != nil {
+				return err
+			}
+			state = loginFailureToState(record)
+			return nil
+		}
+
+		if now.Sub(record.LastFailedAt) > window && (record.LockedUntil == nil || now.After(*record.LockedUntil)) {
+			record.Failures = 0
+			record.LockedUntil = nil
+		}
+		record.Failures++
+		record.LastFailedAt = now
+		if err := tx.Save(&record).Error; err != nil {
+			return err
+		}
+		state = loginFailureToState(record)
+		return nil
+	})
+	return state, err
+}
+
+// Lock 设置锁定截止时间
+func (s *DBLoginAttemptStore) Lock(key string, until time.Time) error {
+	return s.db.Model(&model.LoginFailure{}).Where("counter_key = ?", key).Update("locked_until", until).Error
+}
+
+// Reset 清除失败状态
+func (s *DBLoginAttemptStore) Reset(key string) error {
+	return s.db.Where("counter_key = ?", key).Delete(&model.LoginFailure{}).Error
+}
+
+// Purge 清理过期的失败记录
+func (s *DBLoginAttemptStore) Purge(before time.Time) error {
+	return s.db.Where("last_failed_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, time.Now()).
+		Delete(&model.LoginFailure{}).Error
+}
+
+// loginFailureToState 将数据库记录转换为失败状态
+func loginFailureToState(record model.LoginFailure) LoginFailureState {
+	state := LoginFailureState{
+		Failures:     record.Failures,
+		LastFailedAt: record.LastFailedAt,
+	}
+	if record.LockedUntil != nil {
+		state.LockedUntil = *record.LockedUntil
+	}
+	return state
+}
+
+// loginAttemptStore 全局失败计数存储，由 InitLoginAttemptStore 根据配置初始化
+var loginAttemptStore LoginAttemptStore = NewMemoryLoginAttemptStore()
+
+// InitLoginAttemptStore 根据配置初始化登录失败计数存储，需在数据库初始化之后调用
+func InitLoginAttemptStore() error {
+	switch config.AppConfig.Security.LoginAttemptStore {
+	case "", "database":
+		loginAttemptStore = NewDBLoginAttemptStore(config.DB)
+	case "memory":
+		loginAttemptStore = NewMemoryLoginAttemptStore()
+	default:
+		return errors.New("不支持的登录失败计数存储: " + config.AppConfig.Security.LoginAttemptStore)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"strings"
+	"time"
+)
+
+// LoginLockedError 登录失败次数过多被临时锁定
+type LoginLockedError struct {
+	UnlockAt time.Time
+}
+
+// Error 实现 error 接口
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("登录失败次数过多，请于 %s 后重试", e.UnlockAt.Format(utils.CustomTimeFormat))
+}
+
+// loginGuardPolicy 登录防暴力破解策略
+type loginGuardPolicy struct {
+	maxAccountFailures int
+	maxIPFailures      int
+	failureWindow      time.Duration
+	lockoutDuration    time.Duration
+	maxLockoutDuration time.Duration
+}
+
+// currentLoginGuardPolicy 从配置读取防暴力破解策略
+func currentLoginGuardPolicy() loginGuardPolicy {
+	security := config.AppConfig.Security
+	policy := loginGuardPolicy{
+		maxAccountFailures: security.MaxAccountFailures,
+		maxIPFailures:      security.MaxIPFailures,
+		failureWindow:      config.ParseDurationOrDefault(security.FailureWindow, 15*time.Minute),
+		lockoutDuration:    config.ParseDurationOrDefault(security.LockoutDuration, time.Minute),
+		maxLockoutDuration: config.ParseDurationOrDefault(security.MaxLockoutDuration, time.Hour),
+	}
+	if policy.maxAccountFailures <= 0 {
+		policy.maxAccountFailures = 5
+	}
+	if policy.maxIPFailures <= 0 {
+		policy.maxIPFailures = 20
+	}
+	return policy
+}
+
+// lockoutFor 计算锁定时长：达到阈值后首次锁定 lockoutDuration，之后每次失败翻倍，不超过 maxLockoutDuration
+func (p loginGuardPolicy) lockoutFor(failures, threshold int) time.Duration {
+	if failures < threshold {
+		return 0
+	}
+	lockout := p.lockoutDuration
+	for i := threshold; i < failures && lockout < p.maxLockoutDuration; i++ {
+		lockout *= 2
+	}
+	if lockout > p.maxLockoutDuration {
+		lockout = p.maxLockoutDuration
+	}
+	return lockout
+}
+
+// accountFailureKey 账号维度的计数键
+func accountFailureKey(userID uint, username string) string {
+	if userID != 0 {
+		return fmt.Sprintf("account:%d", userID)
+	}
+	// 不存在的账号按提交的用户名计数，使其表现与真实账号一致
+	return "account:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+// ipFailureKey IP维度的计数键
+func ipFailureKey(clientIP string) string {
+	return "ip:" + clientIP
+}
+
+// checkLoginLocked 检查计数键是否处于锁定状态
+func checkLoginLocked(keys ...string) error {
+	now := time.Now()
+	for _, key := range keys {
+		state, err := loginAttemptStore.Get(key)
+		if err != nil {
+			return err
+		}
+		if state.LockedUntil.After(now) {
+			return &LoginLockedError{UnlockAt: state.LockedUntil}
+		}
+	}
+	return nil
+}
+
+// registerLoginFailure 记录一次登录失败，达到阈值时锁定，返回触发的锁定错误（如有）
+func registerLoginFailure(accountKey, ipKey string) error {
+	policy := currentLoginGuardPolicy()
+	var lockErr *LoginLockedError
+
+	thresholds := map[string]int{
+		accountKey: policy.maxAccountFailures,
+		ipKey:      policy.maxIPFailures,
+	}
+	for key, threshold := range thresholds {
+		state, err := loginAttemptStore.Increment(key, policy.failureWindow)
+		if err != nil {
+			return err
+		}
+
+		lockout := policy.lockoutFor(state.Failures, threshold)
+		if lockout == 0 {
+			continue
+		}
+		unlockAt := time.Now().Add(lockout)
+		if err := loginAttemptStore.Lock(key, unlockAt); err != nil {
+			return err
+		}
+		if lockErr == nil || unlockAt.After(lockErr.UnlockAt) {
+			lockErr = &LoginLockedError{UnlockAt: unlockAt}
+		}
+	}
+
+	if lockErr != nil {
+		return lockErr
+	}
+	return nil
+}
+
+// recordLoginAttempt 写入登录尝试记录和审计日志，写入失败只打印日志
+func recordLoginAttempt(userID uint, username, userAgent string, actx AuditContext, success bool, reason string) {
+	attempt := model.LoginAttempt{
+		UserID:    userID,
+		Username:  truncateString(username, 100),
+		IP:        actx.IP,
+		UserAgent: truncateString(userAgent, 255),
+		Success:   success,
+		Reason:    reason,
+	}
+	if err := config.DB.Create(&attempt).Error; err != nil {
+		fmt.Printf("记录登录尝试失败: %v\n", err)
+	}
+
+	// 审计日志不记录提交的用户名（可能是他人的邮箱或误输入的密码），明细只保存在 login_attempts 中
+	action := AuditLogin
+	details := map[string]interface{}{}
+	if !success {
+		action = AuditLoginFailed
+		details["reason"] = reason
+	}
+	actx.ActorID = userID
+	logAudit(actx, action, AuditTargetUser, userID, nil, details)
+}
+
+// UnlockAccount 解除账号的登录锁定
+func UnlockAccount(userID uint) error {
+	if _, err := GetUserByID(userID); err != nil {
+		return err
+	}
+	return loginAttemptStore.Reset(accountFailureKey(userID, ""))
+}
+
+// PurgeStaleLoginFailures 清理过期的登录失败计数
+func PurgeStaleLoginFailures() error {
+	policy := currentLoginGuardPolicy()
+	window := policy.failureWindow
+	if policy.maxLockoutDuration > window {
+		window = policy.maxLockoutDuration
+	}
+	return loginAttemptStore.Purge(time.Now().Add(-window))
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"gin-blog-system/config"
+	"testing"
+	"time"
+)
+
+func TestLockoutFor(t *testing.T) {
+	policy := loginGuardPolicy{lockoutDuration: time.Minute, maxLockoutDuration: 10 * time.Minute}
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{4, 0},
+		{5, time.Minute},
+		{6, 2 * time.Minute},
+		{7, 4 * time.Minute},
+		{8, 8 * time.Minute},
+		{9, 10 * time.Minute},
+		{100, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := policy.lockoutFor(tt.failures, 5); got != tt.want {
+			t.Errorf("lockoutFor(%d, 5) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestCurrentLoginGuardPolicyDefaults(t *testing.T) {
+	defer func(conf *config.AppConf) { config.AppConfig = conf }(config.AppConfig)
+	config.AppConfig = &config.AppConf{}
+
+	policy := currentLoginGuardPolicy()
+	if policy.maxAccountFailures != 5 || policy.maxIPFailures != 20 {
+		t.Errorf("阈值 = %d/%d, want 5/20", policy.maxAccountFailures, policy.maxIPFailures)
+	}
+	if policy.failureWindow != 15*time.Minute || policy.lockoutDuration != time.Minute || policy.maxLockoutDuration != time.Hour {
+		t.Errorf("时长 = %v/%v/%v, want 15m/1m/1h", policy.failureWindow, policy.lockoutDuration, policy.maxLockoutDuration)
+	}
+}
+
+func TestAccountFailureKey(t *testing.T) {
+	tests := []struct {
+		userID   uint
+		username string
+		want     string
+	}{
+		{42, "alice", "account:42"},
+		{42, "", "account:42"},
+		{0, "Alice", "account:alice"},
+		{0, "  alice ", "account:alice"},
+	}
+	for _, tt := range tests {
+		if got := accountFailureKey(tt.userID, tt.username); got != tt.want {
+			t.Errorf("accountFailureKey(%d, %q) = %q, want %q", tt.userID, tt.username, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterLoginFailure(t *testing.T) {
+	defer func(conf *config.AppConf, store LoginAttemptStore) {
+		config.AppConfig, loginAttemptStore = conf, store
+	}(config.AppConfig, loginAttemptStore)
+
+	config.AppConfig = &config.AppConf{}
+	config.AppConfig.Security.MaxAccountFailures = 3
+	config.AppConfig.Security.MaxIPFailures = 6
+	config.AppConfig.Security.LockoutDuration = "1m"
+	config.AppConfig.Security.MaxLockoutDuration = "4m"
+
+	tests := []struct {
+		name        string
+		accountKey  string
+		wantLocked  bool
+		minDuration time.Duration // 锁定时长下限，用于确认指数递增
+	}{
+		{"第1次失败", "account:1", false, 0},
+		{"第2次失败", "account:1", false, 0},
+		{"达到账号阈值", "account:1", true, time.Minute},
+		{"锁定后继续失败时加倍", "account:1", true, 2 * time.Minute},
+		{"另一个账号未达到阈值", "account:2", false, 0},
+		{"同一IP达到阈值时其他账号也被锁定", "account:3", true, time.Minute},
+	}
+
+	loginAttemptStore = NewMemoryLoginAttemptStore()
+	ipKey := ipFailureKey("192.0.2.1")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registerLoginFailure(tt.accountKey, ipKey)
+			var lockErr *LoginLockedError
+			if locked := errors.As(err, &lockErr); locked != tt.wantLocked {
+				t.Fatalf("registerLoginFailure() error = %v, wantLocked %v", err, tt.wantLocked)
+			}
+			if !tt.wantLocked {
+				return
+			}
+			if remaining := time.Until(lockErr.UnlockAt); remaining < tt.minDuration-time.Second {
+				t.Errorf("锁定时长 = %v, want >= %v", remaining, tt.minDuration)
+			}
+			if err := checkLoginLocked(tt.accountKey, ipKey); !errors.As(err, &lockErr) {
+				t.Errorf("checkLoginLocked() = %v, want LoginLockedError", err)
+			}
+		})
+	}
+
+	// 解除锁定后账号可以重新尝试登录，但 IP 锁定仍然有效
+	if err := loginAttemptStore.Reset("account:1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkLoginLocked("account:1"); err != nil {
+		t.Errorf("checkLoginLocked() 解除锁定后 = %v, want nil", err)
+	}
+	if err := checkLoginLocked(ipKey); err == nil {
+		t.Error("checkLoginLocked() IP 仍应处于锁定状态")
+	}
+}
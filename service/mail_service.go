@@ -0,0 +1,62 @@
+package service
+
+import (
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/utils"
+	"strings"
+)
+
+// mailer 全局邮件发送器，由 InitMailer 根据配置初始化
+var mailer utils.Mailer = utils.NewFileMailer("")
+
+// InitMailer 根据配置初始化邮件发送器
+func InitMailer() error {
+	mailConf := config.AppConfig.Mail
+	switch mailConf.Driver {
+	case "smtp":
+		if mailConf.Host == "" || mailConf.From == "" {
+			return fmt.Errorf("SMTP邮件配置不完整")
+		}
+		port := mailConf.Port
+		if port == "" {
+			port = "25"
+		}
+		mailer = utils.NewSMTPMailer(mailConf.Host, port, mailConf.Username, mailConf.Password, mailConf.From)
+	case "file":
+		mailer = utils.NewFileMailer(mailConf.FilePath)
+	case "", "stdout":
+		mailer = utils.NewFileMailer("")
+	default:
+		return fmt.Errorf("不支持的邮件驱动: %s", mailConf.Driver)
+	}
+	return nil
+}
+
+// SetMailer 替换邮件发送器（用于测试或自定义实现）
+func SetMailer(m utils.Mailer) {
+	mailer = m
+}
+
+// frontendLink 拼接前端页面链接
+func frontendLink(path, token string) string {
+	baseURL := strings.TrimRight(config.AppConfig.App.FrontendURL, "/")
+	if baseURL == "" {
+		baseURL = "http://localhost:3000"
+	}
+	return fmt.Sprintf("%s%s?token=%s", baseURL, path, token)
+}
+
+// sendPasswordResetMail 发送重置密码邮件
+func sendPasswordResetMail(to, username, token string) error {
+	body := fmt.Sprintf("%s，您好：\n\n我们收到了重置您账号密码的请求，请在%d分钟内点击以下链接设置新密码：\n\n%s\n\n如果这不是您本人的操作，请忽略本邮件。",
+		username, int(passwordResetTokenTTL.Minutes()), frontendLink("/reset-password", token))
+	return mailer.Send(to, "重置密码", body)
+}
+
+// sendVerificationMail 发送邮箱验证邮件
+func sendVerificationMail(to, username, token string) error {
+	body := fmt.Sprintf("%s，您好：\n\n请在%d小时内点击以下链接验证您的邮箱：\n\n%s\n\n如果这不是您本人的操作，请忽略本邮件。",
+		username, int(emailVerifyTokenTTL.Hours()), frontendLink("/verify-email", token))
+	return mailer.Send(to, "验证您的邮箱", body)
+}
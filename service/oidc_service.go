@@ -0,0 +1,569 @@
+package service
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// OAuthStateTTL 授权请求状态（及浏览器绑定 Cookie）的有效期
+const OAuthStateTTL = 10 * time.Minute
+
+// 第三方登录相关参数
+const (
+	jwksCacheTTL    = time.Hour
+	oidcHTTPTimeout = 10 * time.Second
+)
+
+// 第三方登录相关错误
+var (
+	ErrUnknownOAuthProvider = errors.New("未知的身份提供方")
+	ErrInvalidOAuthState    = errors.New("无效或已过期的授权请求")
+	ErrIdentityEmailTaken   = errors.New("该邮箱已注册，请先使用密码登录后再绑定第三方账号")
+	ErrIdentityEmailMissing = errors.New("第三方账号未提供邮箱，无法自动创建账号")
+)
+
+// OAuthProviderInfo 身份提供方信息
+type OAuthProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// oidcClaims ID Token 中使用到的声明
+type oidcClaims struct {
+	Nonce             string `json:"nonce"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Picture           string `json:"picture"`
+	jwt.RegisteredClaims
+}
+
+// oidcProvider 已解析端点的身份提供方
+type oidcProvider struct {
+	conf      config.OAuthProviderConf
+	authURL   string
+	tokenURL  string
+	jwksURL   string
+	keys      map[string]crypto.PublicKey
+	keysAt    time.Time
+	keysMutex sync.Mutex
+}
+
+var (
+	oidcProviders      = make(map[string]*oidcProvider)
+	oidcProvidersMutex sync.Mutex
+	oidcHTTPClient     = &http.Client{Timeout: oidcHTTPTimeout}
+)
+
+// ListOAuthProviders 获取已配置的身份提供方
+func ListOAuthProviders() []OAuthProviderInfo {
+	providers := make([]OAuthProviderInfo, 0, len(config.AppConfig.OAuth.Providers))
+	for _, p := range config.AppConfig.OAuth.Providers {
+		displayName := p.DisplayName
+		if displayName == "" {
+			displayName = p.Name
+		}
+		providers = append(providers, OAuthProviderInfo{Name: p.Name, DisplayName: displayName})
+	}
+	return providers
+}
+
+// getOIDCProvider 获取身份提供方，首次使用时进行端点发现
+func getOIDCProvider(name string) (*oidcProvider, error) {
+	oidcProvidersMutex.Lock()
+	defer oidcProvidersMutex.Unlock()
+
+	if p, ok := oidcProviders[name]; ok {
+		return p, nil
+	}
+
+	for _, conf := range config.AppConfig.OAuth.Providers {
+		if conf.Name != name {
+			continue
+		}
+
+		p := &oidcProvider{
+			conf:     conf,
+			authURL:  conf.AuthURL,
+			tokenURL: conf.TokenURL,
+			jwksURL:  conf.JWKSURL,
+		}
+		if p.authURL == "" || p.tokenURL == "" || p.jwksURL == "" {
+			if err := p.discover(); err != nil {
+				return nil, err
+			}
+		}
+		oidcProviders[name] = p
+		return p, nil
+	}
+	return nil, ErrUnknownOAuthProvider
+}
+
+// discover 通过 OpenID Connect Discovery 获取未配置的端点
+func (p *oidcProvider) discover() error {
+	if p.conf.Issuer == "" {
+		return fmt.Errorf("身份提供方 %s 未配置 issuer", p.conf.Name)
+	}
+
+	var doc struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+	discoveryURL := strings.TrimRight(p.conf.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(discoveryURL, &doc); err != nil {
+		return fmt.Errorf("获取身份提供方配置失败: %w", err)
+	}
+	if strings.TrimRight(doc.Issuer, "/") != strings.TrimRight(p.conf.Issuer, "/") {
+		return fmt.Errorf("身份提供方 issuer 不匹配: %s", doc.Issuer)
+	}
+
+	if p.authURL == "" {
+		p.authURL = doc.AuthorizationEndpoint
+	}
+	if p.tokenURL == "" {
+		p.tokenURL = doc.TokenEndpoint
+	}
+	if p.jwksURL == "" {
+		p.jwksURL = doc.JWKSURI
+	}
+	return nil
+}
+
+// publicKey 根据 kid 获取验签公钥，未命中缓存时重新拉取 JWKS（应对密钥轮换）
+func (p *oidcProvider) publicKey(kid string) (crypto.PublicKey, error) {
+	p.keysMutex.Lock()
+	defer p.keysMutex.Unlock()
+
+	if key, ok := p.keys[kid]; ok && time.Since(p.keysAt) < jwksCacheTTL {
+		return key, nil
+	}
+
+	var set utils.JWKSet
+	if err := getJSON(p.jwksURL, &set); err != nil {
+		return nil, fmt.Errorf("获取身份提供方公钥失败: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	p.keys = keys
+	p.keysAt = time.Now()
+
+	key, ok := p.keys[kid]
+	if !ok {
+		// 身份提供方只有一个密钥且未指定 kid 时直接使用
+		if kid == "" && len(p.keys) == 1 {
+			for _, k := range p.keys {
+				return k, nil
+			}
+		}
+		return nil, fmt.Errorf("未找到签名密钥: %s", kid)
+	}
+	return key, nil
+}
+
+// BuildOAuthAuthURL 生成跳转到身份提供方的授权地址（授权码模式 + PKCE）
+// userID 非0时表示为已登录用户绑定第三方身份。
+// 同时返回浏览器绑定值，调用方需将其写入 Cookie，回调时只接受携带同一 Cookie 的请求
+func BuildOAuthAuthURL(providerName string, userID uint) (string, string, error) {
+	p, err := getOIDCProvider(providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	codeVerifier, err := utils.GenerateSecureToken(48)
+	if err != nil {
+		return "", "", err
+	}
+	binding, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	record := model.OAuthState{
+		StateHash:    utils.HashToken(state),
+		BindingHash:  utils.HashToken(binding),
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		UserID:       userID,
+		ExpiresAt:    time.Now().Add(OAuthStateTTL),
+	}
+	if err := config.DB.Create(&record).Error; err != nil {
+		return "", "", err
+	}
+
+	scopes := p.conf.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+	challenge := sha256.Sum256([]byte(codeVerifier))
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.conf.ClientID)
+	params.Set("redirect_uri", p.conf.RedirectURL)
+	params.Set("scope", strings.Join(scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(p.authURL, "?") {
+		separator = "&"
+	}
+	return p.authURL + separator + params.Encode(), binding, nil
+}
+
+// HandleOAuthCallback 处理身份提供方回调：校验 state 及其浏览器绑定，用授权码换取并校验 ID Token，
+// 返回关联（或自动创建）的本地用户。绑定流程的用户取自 state 记录，浏览器绑定保证回调来自发起绑定的浏览器
+func HandleOAuthCallback(providerName, state, code, binding string, actx AuditContext) (*model.User, error) {
+	p, err := getOIDCProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := consumeOAuthState(providerName, state, binding)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := p.exchangeCode(code, record.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := p.verifyIDToken(idToken, record.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.UserID != 0 {
+		return linkIdentity(record.UserID, providerName, claims)
+	}
+	return findOrProvisionUser(providerName, claims, actx)
+}
+
+// consumeOAuthState 校验并消费授权请求状态，binding 必须与发起授权时写入浏览器的值一致
+func consumeOAuthState(providerName, state, binding string) (*model.OAuthState, error) {
+	var record model.OAuthState
+	result := config.DB.Where("state_hash = ?", utils.HashToken(state)).First(&record)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidOAuthState
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if record.Provider != providerName || record.UsedAt != nil || record.ExpiresAt.Before(time.Now()) {
+		return nil, ErrInvalidOAuthState
+	}
+	// 防止他人把自己发起的授权地址交给受害者完成（登录 CSRF、绑定劫持）
+	if binding == "" || record.BindingHash == "" ||
+		subtle.ConstantTimeCompare([]byte(utils.HashToken(binding)), []byte(record.BindingHash)) != 1 {
+		return nil, ErrInvalidOAuthState
+	}
+
+	// 条件更新保证 state 只能使用一次
+	updateResult := config.DB.Model(&model.OAuthState{}).
+		Where("id = ? AND used_at IS NULL", record.ID).
+		Update("used_at", time.Now())
+	if updateResult.Error != nil {
+		return nil, updateResult.Error
+	}
+	if updateResult.RowsAffected == 0 {
+		return nil, ErrInvalidOAuthState
+	}
+	return &record, nil
+}
+
+// exchangeCode 使用授权码换取 ID Token
+func (p *oidcProvider) exchangeCode(code, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.conf.RedirectURL)
+	form.Set("client_id", p.conf.ClientID)
+	form.Set("client_secret", p.conf.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	resp, err := oidcHTTPClient.PostForm(p.tokenURL, form)
+	if err != nil {
+		return "", fmt.Errorf("请求令牌端点失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("令牌端点返回错误: %d %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", fmt.Errorf("解析令牌响应失败: %w", err)
+	}
+	if tokenResp.IDToken == "" {
+		return "", errors.New("令牌响应中缺少 id_token")
+	}
+	return tokenResp.IDToken, nil
+}
+
+// verifyIDToken 校验 ID Token 的签名、签发者、受众、有效期和 nonce
+func (p *oidcProvider) verifyIDToken(idToken, nonce string) (*oidcClaims, error) {
+	claims := &oidcClaims{}
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithAudience(p.conf.ClientID),
+		jwt.WithExpirationRequired(),
+	}
+	if p.conf.Issuer != "" {
+		options = append(options, jwt.WithIssuer(p.conf.Issuer))
+	}
+
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(kid)
+	}, options...)
+	if err != nil {
+		return nil, fmt.Errorf("ID Token 校验失败: %w", err)
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("ID Token nonce 不匹配")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("ID Token 缺少 sub")
+	}
+	return claims, nil
+}
+
+// findOrProvisionUser 查找已关联的本地用户，首次登录时自动创建用户
+func findOrProvisionUser(providerName string, claims *oidcClaims, actx AuditContext) (*model.User, error) {
+	var identity model.UserIdentity
+	result := config.DB.Where("provider = ? AND subject = ?", providerName, claims.Subject).First(&identity)
+	if result.Error == nil {
+		user, err := GetUserByID(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user.Status == model.UserStatusPending {
+			return nil, ErrPendingApproval
+		}
+		if user.Status != model.UserStatusActive {
+			return nil, errors.New("账号已被禁用")
+		}
+		user.Password = ""
+		return user, nil
+	}
+	if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, result.Error
+	}
+
+	// 首次登录自动创建账号同样受注册模式限制，第三方登录无法提供邀请码
+	mode := RegistrationMode()
+	if mode == RegistrationClosed || mode == RegistrationInvite {
+		return nil, ErrRegistrationClosed
+	}
+
+	// 与自助注册一样要求邮箱；不按邮箱自动关联已有账号，避免通过第三方账号接管本地账号
+	if claims.Email == "" {
+		return nil, ErrIdentityEmailMissing
+	}
+	var count int64
+	if err := config.DB.Model(&model.User{}).Where("email = ?", claims.Email).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrIdentityEmailTaken
+	}
+
+	username, err := uniqueUsername(claims)
+	if err != nil {
+		return nil, err
+	}
+	randomPassword, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	user := model.User{
+		Username: username,
+		Nickname: claims.Name,
+		Email:    claims.Email,
+		Password: randomPassword,
+	}
+	if mode == RegistrationApproval {
+		user.Status = model.UserStatusPending
+	}
+
+	// 与自助注册使用相同的创建流程（用户名、邮箱校验和 user.create 审计）
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := createUser(tx, &user, actx); err != nil {
+			return err
+		}
+		if claims.EmailVerified {
+			if err := tx.Model(&user).Update("email_verified", true).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&model.UserIdentity{
+			UserID:   user.ID,
+			Provider: providerName,
+			Subject:  claims.Subject,
+			Email:    claims.Email,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == model.UserStatusPending {
+		return nil, ErrPendingApproval
+	}
+
+	user.Password = ""
+	return &user, nil
+}
+
+// linkIdentity 将第三方身份绑定到已登录的本地用户
+func linkIdentity(userID uint, providerName string, claims *oidcClaims) (*model.User, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing model.UserIdentity
+	result := config.DB.Where("provider = ? AND subject = ?", providerName, claims.Subject).First(&existing)
+	if result.Error == nil {
+		if existing.UserID != userID {
+			return nil, errors.New("该第三方账号已绑定其他用户")
+		}
+		user.Password = ""
+		return user, nil
+	}
+	if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, result.Error
+	}
+
+	identity := model.UserIdentity{
+		UserID:   userID,
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+	if err := config.DB.Create(&identity).Error; err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+// ListUserIdentities 获取用户绑定的第三方身份
+func ListUserIdentities(userID uint) ([]model.UserIdentity, error) {
+	var identities []model.UserIdentity
+	result := config.DB.Where("user_id = ?", userID).Find(&identities)
+	return identities, result.Error
+}
+
+// UnlinkIdentity 解除第三方身份绑定
+func UnlinkIdentity(userID, identityID uint) error {
+	result := config.DB.Where("id = ? AND user_id = ?", identityID, userID).Delete(&model.UserIdentity{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("绑定关系不存在")
+	}
+	return nil
+}
+
+// usernameSanitizer 用户名中允许的字符之外的部分
+var usernameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// uniqueUsername 根据第三方资料生成不重复的用户名
+func uniqueUsername(claims *oidcClaims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" && claims.Email != "" {
+		base = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+	base = usernameSanitizer.ReplaceAllString(base, "")
+	if base == "" {
+		base = "user"
+	}
+	base = truncateString(base, 40)
+
+	candidate := base
+	for i := 0; i < 10; i++ {
+		// 不符合注册规则的用户名（如系统保留的用户名）与已被占用的一样加后缀重试
+		if ValidateUsername(candidate) == nil {
+			var count int64
+			if err := config.DB.Model(&model.User{}).Where("username = ?", candidate).Count(&count).Error; err != nil {
+				return "", err
+			}
+			if count == 0 {
+				return candidate, nil
+			}
+		}
+		suffix, err := utils.GenerateSecureToken(3)
+		if err != nil {
+			return "", err
+		}
+		candidate = base + "_" + strings.ToLower(usernameSanitizer.ReplaceAllString(suffix, ""))
+	}
+	return "", errors.New("生成用户名失败")
+}
+
+// getJSON 请求URL并解析JSON响应
+func getJSON(rawURL string, v interface{}) error {
+	resp, err := oidcHTTPClient.Get(rawURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("请求 %s 返回 %d", rawURL, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor 无效的分页游标
+var ErrInvalidCursor = errors.New("无效的分页游标")
+
+// PageRequest 分页参数：Cursor 不为空时使用游标分页，否则按页码分页
+type PageRequest struct {
+	Page      int    // 页码，从 1 开始，游标分页时忽略
+	PageSize  int    // 每页数量
+	Cursor    string // 上一次返回的 next_cursor 或 prev_cursor
+	WithTotal bool   // 是否计算总数
+}
+
+// PageResult 分页结果，没有下一页（上一页）时对应的游标为空
+type PageResult struct {
+	Total      *int64 // 总数，未要求计算时为 nil
+	NextCursor string
+	PrevCursor string
+}
+
+// 排序列的值类型，用于从游标中还原排序值
+const (
+	keysetInt = iota
+	keysetString
+	keysetTime
+)
+
+// keysetColumn 游标分页的排序列
+type keysetColumn struct {
+	Name     string // 排序列，如 articles.created_at
+	Desc     bool   // 是否倒序
+	Kind     int    // 值类型
+	Nullable bool   // 是否可能为 NULL（NULL 视为最小值，与 MySQL 的排序一致）
+}
+
+// keysetSpec 游标分页的排序方式
+type keysetSpec struct {
+	Scope   string         // 列表和排序方式的标识，游标只能用于生成它的列表
+	Columns []keysetColumn // 排序列，最后一列必须唯一（通常为ID）
+	Values  func(item interface{}) []interface{}
+}
+
+// listCursor 分页游标：边界记录的排序值，签名后编码为不透明字符串
+type listCursor struct {
+	Scope    string        `json:"s"`
+	Values   []interface{} `json:"v"`
+	Backward bool          `json:"b,omitempty"` // 为 true 时向前翻页（prev_cursor）
+}
+
+// cursorKey 游标签名密钥，由加密密钥派生
+func cursorKey() []byte {
+	sum := sha256.Sum256(append([]byte("list-cursor:"), encryptionKey()...))
+	return sum[:]
+}
+
+// encodeCursor 将游标编码为 base64(JSON).base64(HMAC-SHA256)
+func encodeCursor(cursor listCursor) string {
+	payload, _ := json.Marshal(cursor)
+	mac := hmac.New(sha256.New, cursorKey())
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// decodeCursor 校验签名并解析游标，游标必须由同一列表和排序方式生成
+func decodeCursor(value string, spec keysetSpec) (*listCursor, error) {
+	parts := strings.Split(value, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	mac := hmac.New(sha256.New, cursorKey())
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor listCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Scope != spec.Scope || len(cursor.Values) != len(spec.Columns) {
+		return nil, fmt.Errorf("%w：游标与当前列表或排序方式不匹配", ErrInvalidCursor)
+	}
+
+	// JSON 中的数字和时间需要按列的类型还原
+	for i, column := range spec.Columns {
+		v := cursor.Values[i]
+		if v == nil {
+			if !column.Nullable {
+				return nil, ErrInvalidCursor
+			}
+			continue
+		}
+		switch column.Kind {
+		case keysetInt:
+			n, ok := v.(float64)
+			if !ok {
+				return nil, ErrInvalidCursor
+			}
+			cursor.Values[i] = int64(n)
+		case keysetString:
+			if _, ok := v.(string); !ok {
+				return nil, ErrInvalidCursor
+			}
+		case keysetTime:
+			s, ok := v.(string)
+			if !ok {
+				return nil, ErrInvalidCursor
+			}
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			cursor.Values[i] = t
+		}
+	}
+	return &cursor, nil
+}
+
+// keysetCondition 生成“排在游标之后”的查询条件：
+// (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ...，比较方向由各列的排序方向和翻页方向决定
+func keysetCondition(columns []keysetColumn, values []interface{}, backward bool) (string, []interface{}) {
+	var terms []string
+	var args []interface{}
+	for i, column := range columns {
+		var parts []string
+		var termArgs []interface{}
+		for j := 0; j < i; j++ {
+			if values[j] == nil {
+				parts = append(parts, columns[j].Name+" IS NULL")
+			} else {
+				parts = append(parts, columns[j].Name+" = ?")
+				termArgs = append(termArgs, values[j])
+			}
+		}
+
+		greater := column.Desc == backward
+		switch {
+		case values[i] == nil && greater:
+			parts = append(parts, column.Name+" IS NOT NULL")
+		case values[i] == nil:
+			// 没有比 NULL 更小的值
+			continue
+		case greater:
+			parts = append(parts, column.Name+" > ?")
+			termArgs = append(termArgs, values[i])
+		case column.Nullable:
+			parts = append(parts, "("+column.Name+" < ? OR "+column.Name+" IS NULL)")
+			termArgs = append(termArgs, values[i])
+		default:
+			parts = append(parts, column.Name+" < ?")
+			termArgs = append(termArgs, values[i])
+		}
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+		args = append(args, termArgs...)
+	}
+	if len(terms) == 0 {
+		return "1 = 0", nil
+	}
+	return strings.Join(terms, " OR "), args
+}
+
+// findPage 按分页参数查询一页记录，dest 为切片指针。
+// 游标分页按排序列比较取游标之后的记录，页码分页使用 Offset；两种方式都返回前后页的游标
+func findPage(db *gorm.DB, spec keysetSpec, req PageRequest, dest interface{}) (*PageResult, error) {
+	result := &PageResult{}
+	if req.WithTotal {
+		var total int64
+		if err := db.Count(&total).Error; err != nil {
+			return nil, err
+		}
+		result.Total = &total
+	}
+
+	backward := false
+	if req.Cursor != "" {
+		cursor, err := decodeCursor(req.Cursor, spec)
+		if err != nil {
+			return nil, err
+		}
+		backward = cursor.Backward
+		condition, args := keysetCondition(spec.Columns, cursor.Values, backward)
+		db = db.Where(condition, args...)
+	} else {
+		db = db.Offset((req.Page - 1) * req.PageSize)
+	}
+
+	// 向前翻页时反向排序，查询后再恢复顺序
+	for _, column := range spec.Columns {
+		if column.Desc != backward {
+			db = db.Order(column.Name + " DESC")
+		} else {
+			db = db.Order(column.Name)
+		}
+	}
+
+	// 多查询一条用于判断是否还有更多记录
+	if err := db.Limit(req.PageSize + 1).Find(dest).Error; err != nil {
+		return nil, err
+	}
+
+	rows := reflect.ValueOf(dest).Elem()
+	hasMore := rows.Len() > req.PageSize
+	if hasMore {
+		rows.Set(rows.Slice(0, req.PageSize))
+	}
+	if backward {
+		for i, j := 0, rows.Len()-1; i < j; i, j = i+1, j-1 {
+			a, b := rows.Index(i).Interface(), rows.Index(j).Interface()
+			rows.Index(i).Set(reflect.ValueOf(b))
+			rows.Index(j).Set(reflect.ValueOf(a))
+		}
+	}
+	if rows.Len() == 0 {
+		return result, nil
+	}
+
+	first := encodeCursor(listCursor{Scope: spec.Scope, Values: spec.Values(rows.Index(0).Addr().Interface()), Backward: true})
+	last := encodeCursor(listCursor{Scope: spec.Scope, Values: spec.Values(rows.Index(rows.Len() - 1).Addr().Interface())})
+	switch {
+	case backward:
+		// 从后一页翻回来，后面一定还有记录
+		result.NextCursor = last
+		if hasMore {
+			result.PrevCursor = first
+		}
+	case req.Cursor != "":
+		if hasMore {
+			result.NextCursor = last
+		}
+		result.PrevCursor = first
+	default:
+		if hasMore {
+			result.NextCursor = last
+		}
+		if req.Page > 1 {
+			result.PrevCursor = first
+		}
+	}
+	return result, nil
+}
+
+// cursorTime 将时间转换为游标中的值
+func cursorTime(t time.Time) interface{} {
+	return t.Format(time.RFC3339Nano)
+}
+
+// cursorNullableTime 将可能为空的时间转换为游标中的值
+func cursorNullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return cursorTime(*t)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"gin-blog-system/config"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testKeysetSpec 按发布时间（可为空）倒序、ID倒序排列的测试列表
+var testKeysetSpec = keysetSpec{
+	Scope: "test:published_at",
+	Columns: []keysetColumn{
+		{Name: "published_at", Desc: true, Kind: keysetTime, Nullable: true},
+		{Name: "id", Desc: true, Kind: keysetInt},
+	},
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	defer func(conf *config.AppConf) { config.AppConfig = conf }(config.AppConfig)
+	config.AppConfig = &config.AppConf{}
+	config.AppConfig.App.EncryptionKey = "cursor-test-key"
+
+	publishedAt := time.Date(2026, 10, 17, 9, 30, 0, 123456789, time.UTC)
+	tests := []struct {
+		name   string
+		cursor listCursor
+		want   []interface{}
+	}{
+		{
+			name:   "向后翻页",
+			cursor: listCursor{Scope: testKeysetSpec.Scope, Values: []interface{}{cursorTime(publishedAt), 42}},
+			want:   []interface{}{publishedAt, int64(42)},
+		},
+		{
+			name:   "向前翻页",
+			cursor: listCursor{Scope: testKeysetSpec.Scope, Values: []interface{}{cursorTime(publishedAt), 7}, Backward: true},
+			want:   []interface{}{publishedAt, int64(7)},
+		},
+		{
+			name:   "可为空的列",
+			cursor: listCursor{Scope: testKeysetSpec.Scope, Values: []interface{}{cursorNullableTime(nil), 3}},
+			want:   []interface{}{nil, int64(3)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(encodeCursor(tt.cursor), testKeysetSpec)
+			if err != nil {
+				t.Fatalf("decodeCursor() error = %v", err)
+			}
+			if got.Backward != tt.cursor.Backward {
+				t.Errorf("Backward = %v, want %v", got.Backward, tt.cursor.Backward)
+			}
+			if len(got.Values) != len(tt.want) {
+				t.Fatalf("Values = %v, want %v", got.Values, tt.want)
+			}
+			for i, want := range tt.want {
+				if wantTime, ok := want.(time.Time); ok {
+					if gotTime, ok := got.Values[i].(time.Time); !ok || !gotTime.Equal(wantTime) {
+						t.Errorf("Values[%d] = %v, want %v", i, got.Values[i], want)
+					}
+					continue
+				}
+				if !reflect.DeepEqual(got.Values[i], want) {
+					t.Errorf("Values[%d] = %#v, want %#v", i, got.Values[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsInvalid(t *testing.T) {
+	defer func(conf *config.AppConf) { config.AppConfig = conf }(config.AppConfig)
+	config.AppConfig = &config.AppConf{}
+	config.AppConfig.App.EncryptionKey = "cursor-test-key"
+
+	valid := encodeCursor(listCursor{Scope: testKeysetSpec.Scope, Values: []interface{}{cursorTime(time.Now()), 1}})
+	parts := strings.Split(valid, ".")
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	forged := strings.Replace(string(payload), `1]`, `2]`, 1)
+
+	tests := []struct {
+		name   string
+		cursor func() string
+	}{
+		{"空字符串", func() string { return "" }},
+		{"缺少签名", func() string { return parts[0] }},
+		{"多余的分段", func() string { return valid + ".x" }},
+		{"不是 base64", func() string { return "!!!." + parts[1] }},
+		{"篡改内容", func() string { return base64.RawURLEncoding.EncodeToString([]byte(forged)) + "." + parts[1] }},
+		{"篡改签名", func() string { return parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte("forged")) }},
+		{"其他列表的游标", func() string {
+			return encodeCursor(listCursor{Scope: "test:other", Values: []interface{}{cursorTime(time.Now()), 1}})
+		}},
+		{"排序列数量不匹配", func() string { return encodeCursor(listCursor{Scope: testKeysetSpec.Scope, Values: []interface{}{1}}) }},
+		{"不可为空的列为空", func() string {
+			return encodeCursor(listCursor{Scope: testKeysetSpec.Scope, Values: []interface{}{cursorTime(time.Now()), nil}})
+		}},
+		{"时间格式错误", func() string {
+			return encodeCursor(listCursor{Scope: testKeysetSpec.Scope, Values: []interface{}{"yesterday", 1}})
+		}},
+		{"ID 类型错误", func() string {
+			return encodeCursor(listCursor{Scope: testKeysetSpec.Scope, Values: []interface{}{cursorTime(time.Now()), "1"}})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.cursor(), testKeysetSpec); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("decodeCursor() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsOtherKey(t *testing.T) {
+	defer func(conf *config.AppConf) { config.AppConfig = conf }(config.AppConfig)
+	config.AppConfig = &config.AppConf{}
+	config.AppConfig.App.EncryptionKey = "old-key"
+	cursor := encodeCursor(listCursor{Scope: testKeysetSpec.Scope, Values: []interface{}{cursorTime(time.Now()), 1}})
+
+	config.AppConfig.App.EncryptionKey = "new-key"
+	if _, err := decodeCursor(cursor, testKeysetSpec); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("decodeCursor() error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	publishedAt := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		values   []interface{}
+		backward bool
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "向后翻页",
+			values:   []interface{}{publishedAt, int64(5)},
+			want:     "((published_at < ? OR published_at IS NULL)) OR (published_at = ? AND id < ?)",
+			wantArgs: []interface{}{publishedAt, publishedAt, int64(5)},
+		},
+		{
+			name:     "向前翻页",
+			values:   []interface{}{publishedAt, int64(5)},
+			backward: true,
+			want:     "(published_at > ?) OR (published_at = ? AND id > ?)",
+			wantArgs: []interface{}{publishedAt, publishedAt, int64(5)},
+		},
+		{
+			name:     "向后翻页时 NULL 之后只剩同为 NULL 的记录",
+			values:   []interface{}{nil, int64(5)},
+			want:     "(published_at IS NULL AND id < ?)",
+			wantArgs: []interface{}{int64(5)},
+		},
+		{
+			name:     "向前翻页时 NULL 之前是所有非 NULL 的记录",
+			values:   []interface{}{nil, int64(5)},
+			backward: true,
+			want:     "(published_at IS NOT NULL) OR (published_at IS NULL AND id > ?)",
+			wantArgs: []interface{}{int64(5)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args := keysetCondition(testKeysetSpec.Columns, tt.values, tt.backward)
+			if got != tt.want {
+				t.Errorf("condition = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
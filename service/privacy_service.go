@@ -0,0 +1,367 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// deletedUserUsername 匿名账号的用户名，注销用户的评论（以及按策略保留的文章）转移到该账号。
+// 匿名账号按 IsSystem 标记查找，该用户名保留，不能注册
+const deletedUserUsername = "deleted_user"
+
+// erasureJobStaleAfter 执行中的注销任务超过该时间未更新时视为中断，可被重新执行
+const erasureJobStaleAfter = 10 * time.Minute
+
+// erasureJobHeartbeat 执行中的注销任务刷新 updated_at 的间隔，避免耗时较长的任务被误判为中断
+const erasureJobHeartbeat = erasureJobStaleAfter / 5
+
+// UserExport 用户个人数据导出内容
+type UserExport struct {
+	Profile      *model.UserResponse                 `json:"profile"`
+	Articles     []model.ArticleResponse             `json:"articles"`
+	Series       []model.SeriesResponse              `json:"series"`
+	Comments     []model.CommentResponse             `json:"comments"`
+	Likes        []model.Like                        `json:"likes"`
+	Following    []model.Follow                      `json:"following"`
+	FollowedTags []model.TagFollow                   `json:"followed_tags"`
+	Sessions     []model.SessionResponse             `json:"sessions"`
+	Identities   []model.UserIdentity                `json:"identities"`
+	AccessTokens []model.PersonalAccessTokenResponse `json:"access_tokens"`
+	LoginHistory []model.LoginAttempt                `json:"login_history"`
+	Uploads      []model.Upload                      `json:"uploads"`
+	ExportedAt   utils.CustomTime                    `json:"exported_at"`
+}
+
+// ExportUserData 收集用户的全部个人数据
+func ExportUserData(userID uint) (*UserExport, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &UserExport{
+		Profile:    user.ConvertToUserResponse(),
+		ExportedAt: utils.CustomTime{Time: time.Now()},
+	}
+
+	var articles []model.Article
+	if err := config.DB.Where("user_id = ?", userID).Preload("Category").Preload("Tags").Order("created_at").Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	export.Articles = make([]model.ArticleResponse, len(articles))
+	for i, article := range articles {
+		export.Articles[i] = *article.ConvertToArticleResponse()
+	}
+
+	var seriesIDs []uint
+	if err := config.DB.Model(&model.Series{}).Where("user_id = ?", userID).Order("created_at").Pluck("id", &seriesIDs).Error; err != nil {
+		return nil, err
+	}
+	export.Series = make([]model.SeriesResponse, len(seriesIDs))
+	for i, seriesID := range seriesIDs {
+		series, err := GetSeriesByID(seriesID, userID, user.Role)
+		if err != nil {
+			return nil, err
+		}
+		export.Series[i] = *series
+	}
+
+	var comments []model.Comment
+	if err := config.DB.Where("user_id = ?", userID).Order("created_at").Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	export.Comments = make([]model.CommentResponse, len(comments))
+	for i, comment := range comments {
+		export.Comments[i] = *comment.ConvertToCommentResponse()
+	}
+
+	if err := config.DB.Where("user_id = ?", userID).Order("created_at").Find(&export.Likes).Error; err != nil {
+		return nil, err
+	}
+
+	if err := config.DB.Where("follower_id = ?", userID).Order("created_at").Find(&export.Following).Error; err != nil {
+		return nil, err
+	}
+	if err := config.DB.Where("user_id = ?", userID).Order("created_at").Find(&export.FollowedTags).Error; err != nil {
+		return nil, err
+	}
+
+	if export.Sessions, err = ListUserSessions(userID, 0); err != nil {
+		return nil, err
+	}
+	if export.Identities, err = ListUserIdentities(userID); err != nil {
+		return nil, err
+	}
+	if export.AccessTokens, err = ListPersonalAccessTokens(userID); err != nil {
+		return nil, err
+	}
+
+	if err := config.DB.Where("user_id = ?", userID).Order("created_at").Find(&export.LoginHistory).Error; err != nil {
+		return nil, err
+	}
+	if err := config.DB.Where("user_id = ?", userID).Order("created_at").Find(&export.Uploads).Error; err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// WriteZip 将导出内容写为ZIP归档：data.json 包含全部结构化数据，uploads/ 目录包含上传的文件
+func (e *UserExport) WriteZip(w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	file, err := archive.Create("data.json")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+
+	for _, upload := range e.Uploads {
+		if err := addFileToZip(archive, path.Join("uploads", filepath.ToSlash(upload.Path)),
+			filepath.Join(config.AppConfig.Upload.SavePath, upload.Path)); err != nil {
+			// 文件可能已被手动删除，跳过即可
+			fmt.Printf("导出上传文件失败: %v\n", err)
+		}
+	}
+
+	return archive.Close()
+}
+
+// addFileToZip 将磁盘文件写入ZIP归档
+func addFileToZip(archive *zip.Writer, name, filePath string) error {
+	src, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+// erasureArticlePolicy 注销账号时文章的处理策略
+func erasureArticlePolicy() string {
+	if config.AppConfig.Privacy.ErasureArticlePolicy == model.ErasureArticleDelete {
+		return model.ErasureArticleDelete
+	}
+	return model.ErasureArticleReassign
+}
+
+// RequestUserErasure 创建账号注销任务：立即禁用账号并作废其全部令牌，数据删除在后台执行
+func RequestUserErasure(userID, requestedBy uint) (*model.ErasureJob, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSystem {
+		return nil, errors.New("匿名账号不能注销")
+	}
+
+	// 已有未完成的任务时直接返回
+	var existing model.ErasureJob
+	result := config.DB.Where("user_id = ? AND status IN ?", userID,
+		[]string{model.ErasureStatusPending, model.ErasureStatusRunning}).Limit(1).Find(&existing)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if existing.ID != 0 {
+		return &existing, nil
+	}
+
+	jobID, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return nil, err
+	}
+	job := model.ErasureJob{
+		JobID:         jobID,
+		UserID:        userID,
+		RequestedBy:   requestedBy,
+		ArticlePolicy: erasureArticlePolicy(),
+		Status:        model.ErasureStatusPending,
+	}
+	if err := config.DB.Create(&job).Error; err != nil {
+		return nil, err
+	}
+
+	if err := config.DB.Model(&model.User{}).Where("id = ?", userID).Update("status", model.UserStatusDisabled).Error; err != nil {
+		return nil, err
+	}
+	if err := RevokeAllUserTokens(userID, "account_erasure"); err != nil {
+		return nil, err
+	}
+
+	go runErasureJob(job.ID)
+	return &job, nil
+}
+
+// GetErasureJob 根据公开的任务ID查询注销任务
+func GetErasureJob(jobID string) (*model.ErasureJob, error) {
+	var job model.ErasureJob
+	result := config.DB.Where("job_id = ?", jobID).First(&job)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, errors.New("任务不存在")
+	}
+	return &job, result.Error
+}
+
+// ResumeErasureJobs 执行等待中或已中断的注销任务，服务启动时调用
+func ResumeErasureJobs() {
+	var jobs []model.ErasureJob
+	result := config.DB.Where("status = ? OR (status = ? AND updated_at < ?)",
+		model.ErasureStatusPending, model.ErasureStatusRunning, time.Now().Add(-erasureJobStaleAfter)).Find(&jobs)
+	if result.Error != nil {
+		fmt.Printf("查询注销任务失败: %v\n", result.Error)
+		return
+	}
+
+	for _, job := range jobs {
+		if job.Status == model.ErasureStatusRunning {
+			result := config.DB.Model(&model.ErasureJob{}).
+				Where("id = ? AND status = ? AND updated_at < ?", job.ID, model.ErasureStatusRunning, time.Now().Add(-erasureJobStaleAfter)).
+				Update("status", model.ErasureStatusPending)
+			if result.Error != nil {
+				fmt.Printf("重置注销任务失败: %v\n", result.Error)
+				continue
+			}
+		}
+		runErasureJob(job.ID)
+	}
+}
+
+// runErasureJob 执行注销任务，条件更新保证多实例部署时同一任务只会被执行一次
+func runErasureJob(id uint) {
+	now := time.Now()
+	claim := config.DB.Model(&model.ErasureJob{}).
+		Where("id = ? AND status = ?", id, model.ErasureStatusPending).
+		Updates(map[string]interface{}{"status": model.ErasureStatusRunning, "started_at": now})
+	if claim.Error != nil || claim.RowsAffected == 0 {
+		return
+	}
+
+	// 执行期间定期刷新 updated_at，其他实例不会把仍在执行的任务当作中断任务重新执行
+	stop := make(chan struct{})
+	defer close(stop)
+	go keepErasureJobAlive(id, stop)
+
+	var job model.ErasureJob
+	if err := config.DB.First(&job, id).Error; err != nil {
+		fmt.Printf("查询注销任务失败: %v\n", err)
+		return
+	}
+
+	updates := map[string]interface{}{
+		"status": model.ErasureStatusCompleted,
+	}
+	if err := DeleteUser(job.UserID, job.ArticlePolicy, AuditContext{ActorID: job.RequestedBy}); err != nil {
+		fmt.Printf("注销用户 %d 失败: %v\n", job.UserID, err)
+		updates["status"] = model.ErasureStatusFailed
+		updates["error"] = err.Error()
+	}
+	updates["finished_at"] = time.Now()
+
+	if err := config.DB.Model(&job).Updates(updates).Error; err != nil {
+		fmt.Printf("更新注销任务状态失败: %v\n", err)
+	}
+}
+
+// keepErasureJobAlive 定期刷新执行中任务的 updated_at，直到 stop 被关闭
+func keepErasureJobAlive(id uint, stop <-chan struct{}) {
+	ticker := time.NewTicker(erasureJobHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			err := config.DB.Model(&model.ErasureJob{}).
+				Where("id = ? AND status = ?", id, model.ErasureStatusRunning).
+				Update("updated_at", time.Now()).Error
+			if err != nil {
+				fmt.Printf("刷新注销任务状态失败: %v\n", err)
+			}
+		}
+	}
+}
+
+// getDeletedUserAccount 获取匿名账号，不存在时创建（禁用状态，无法登录）。
+// 只按系统账号标记查找，不信任用户可以选择的用户名
+func getDeletedUserAccount(tx *gorm.DB) (*model.User, error) {
+	var ghost model.User
+	result := tx.Where("is_system = ?", true).Order("id").Limit(1).Find(&ghost)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if ghost.ID != 0 {
+		return &ghost, nil
+	}
+
+	// 升级前创建的匿名账号（或抢注的同名账号）没有系统标记，新账号使用带随机后缀的用户名
+	username := deletedUserUsername
+	for i := 0; ; i++ {
+		var count int64
+		if err := tx.Model(&model.User{}).Where("username = ? OR email = ?", username, username+"@users.invalid").Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			break
+		}
+		if i >= 10 {
+			return nil, errors.New("创建匿名账号失败")
+		}
+		suffix, err := utils.GenerateSecureToken(4)
+		if err != nil {
+			return nil, err
+		}
+		username = deletedUserUsername + "_" + strings.ToLower(usernameSanitizer.ReplaceAllString(suffix, ""))
+	}
+
+	randomPassword, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := HashPassword(randomPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	ghost = model.User{
+		Username: username,
+		Nickname: "已注销用户",
+		Email:    username + "@users.invalid",
+		Password: hashedPassword,
+		Role:     model.RoleReader,
+		IsSystem: true,
+	}
+	if err := tx.Create(&ghost).Error; err != nil {
+		return nil, err
+	}
+	// status 字段有数据库默认值，零值需要单独更新
+	if err := tx.Model(&ghost).Update("status", model.UserStatusDisabled).Error; err != nil {
+		return nil, err
+	}
+	return &ghost, nil
+}
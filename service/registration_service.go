@@ -0,0 +1,242 @@
+package service
+
+import (
+	"errors"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 注册模式
+const (
+	RegistrationOpen     = "open"     // 开放注册
+	RegistrationInvite   = "invite"   // 需要邀请码
+	RegistrationApproval = "approval" // 注册后需管理员审核
+	RegistrationClosed   = "closed"   // 关闭注册
+)
+
+// 注册相关错误
+var (
+	ErrRegistrationClosed = errors.New("当前不开放注册")
+	ErrInviteCodeRequired = errors.New("注册需要邀请码")
+	ErrInvalidInviteCode  = errors.New("邀请码无效、已过期或已用完")
+	ErrPendingApproval    = errors.New("账号正在等待管理员审核")
+)
+
+// RegisterInput 注册信息
+type RegisterInput struct {
+	Username   string
+	Password   string
+	Email      string
+	Nickname   string
+	InviteCode string
+}
+
+// RegistrationMode 当前注册模式，未配置时为开放注册，无法识别的配置按关闭注册处理
+func RegistrationMode() string {
+	switch mode := config.AppConfig.App.RegistrationMode; mode {
+	case "":
+		return RegistrationOpen
+	case RegistrationOpen, RegistrationInvite, RegistrationApproval, RegistrationClosed:
+		return mode
+	default:
+		return RegistrationClosed
+	}
+}
+
+// RegisterUser 按注册模式注册新用户：invite 模式消耗一次邀请码，approval 模式创建待审核账号
+func RegisterUser(input RegisterInput, actx AuditContext) (*model.User, error) {
+	mode := RegistrationMode()
+	if mode == RegistrationClosed {
+		return nil, ErrRegistrationClosed
+	}
+
+	input.Username = strings.TrimSpace(input.Username)
+	if input.Username == "" {
+		return nil, errors.New("用户名不能为空")
+	}
+	if len(input.Password) < 6 {
+		return nil, errors.New("密码长度不能少于6位")
+	}
+
+	user := &model.User{
+		Username: input.Username,
+		Nickname: input.Nickname,
+		Email:    input.Email,
+		Password: input.Password,
+		Status:   model.UserStatusActive,
+	}
+	if mode == RegistrationApproval {
+		user.Status = model.UserStatusPending
+	}
+
+	if mode != RegistrationInvite {
+		if err := CreateUser(user, actx); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	if input.InviteCode == "" {
+		return nil, ErrInviteCodeRequired
+	}
+
+	// 开始事务
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := consumeInviteCode(tx, input.InviteCode); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := createUser(tx, user, actx); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// consumeInviteCode 校验邀请码并增加使用次数，条件更新保证并发注册时不会超过最大使用次数
+func consumeInviteCode(tx *gorm.DB, code string) error {
+	var invite model.InviteCode
+	result := tx.Where("code_hash = ?", utils.HashToken(strings.TrimSpace(code))).First(&invite)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return ErrInvalidInviteCode
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if invite.RevokedAt != nil || (invite.ExpiresAt != nil && invite.ExpiresAt.Before(time.Now())) {
+		return ErrInvalidInviteCode
+	}
+
+	updateResult := tx.Model(&model.InviteCode{}).
+		Where("id = ? AND use_count < max_uses AND revoked_at IS NULL", invite.ID).
+		UpdateColumn("use_count", gorm.Expr("use_count + ?", 1))
+	if updateResult.Error != nil {
+		return updateResult.Error
+	}
+	if updateResult.RowsAffected == 0 {
+		return ErrInvalidInviteCode
+	}
+	return nil
+}
+
+// CreateInviteCode 创建邀请码，邀请码明文只在创建时返回一次
+func CreateInviteCode(createdBy uint, maxUses int, expiresAt *time.Time, note string) (*model.InviteCodeResponse, error) {
+	if maxUses < 1 {
+		return nil, errors.New("最大使用次数至少为1")
+	}
+	if expiresAt != nil && expiresAt.Before(time.Now()) {
+		return nil, errors.New("过期时间不能早于当前时间")
+	}
+
+	code, err := utils.GenerateSecureToken(12)
+	if err != nil {
+		return nil, err
+	}
+
+	invite := model.InviteCode{
+		CodeHash:  utils.HashToken(code),
+		Prefix:    code[:6],
+		Note:      truncateString(strings.TrimSpace(note), 255),
+		MaxUses:   maxUses,
+		ExpiresAt: expiresAt,
+		CreatedBy: createdBy,
+	}
+	if err := config.DB.Create(&invite).Error; err != nil {
+		return nil, err
+	}
+
+	response := invite.ConvertToInviteCodeResponse()
+	response.Code = code
+	return response, nil
+}
+
+// ListInviteCodes 获取全部邀请码
+func ListInviteCodes() ([]model.InviteCodeResponse, error) {
+	var invites []model.InviteCode
+	result := config.DB.Order("created_at DESC").Find(&invites)
+
+	// 转换为响应结构
+	responses := make([]model.InviteCodeResponse, len(invites))
+	for i, invite := range invites {
+		responses[i] = *invite.ConvertToInviteCodeResponse()
+	}
+
+	return responses, result.Error
+}
+
+// RevokeInviteCode 作废邀请码
+func RevokeInviteCode(id uint) error {
+	result := config.DB.Model(&model.InviteCode{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("邀请码不存在或已作废")
+	}
+	return nil
+}
+
+// ListPendingUsers 获取等待审核的用户
+func ListPendingUsers() ([]model.UserResponse, error) {
+	var users []model.User
+	result := config.DB.Where("status = ?", model.UserStatusPending).Order("created_at").Find(&users)
+
+	// 转换为响应结构
+	responses := make([]model.UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = *user.ConvertToUserResponse()
+	}
+
+	return responses, result.Error
+}
+
+// ApproveUser 审核通过待审核的用户，并记录状态变更的审计日志
+func ApproveUser(id uint, actx AuditContext) error {
+	user, err := GetUserByID(id)
+	if err != nil {
+		return err
+	}
+
+	before := *user
+	result := config.DB.Model(&model.User{}).
+		Where("id = ? AND status = ?", id, model.UserStatusPending).
+		Update("status", model.UserStatusActive)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("用户不存在或不是待审核状态")
+	}
+
+	user.Status = model.UserStatusActive
+	logAudit(actx, AuditUserStatusUpdate, AuditTargetUser, id, before, *user)
+	return nil
+}
+
+// RejectUser 拒绝待审核的用户并删除该账号，释放用户名和邮箱
+func RejectUser(id uint, actx AuditContext) error {
+	user, err := GetUserByID(id)
+	if err != nil {
+		return err
+	}
+	if user.Status != model.UserStatusPending {
+		return errors.New("用户不是待审核状态")
+	}
+	return DeleteUser(id, model.ErasureArticleDelete, actx)
+}
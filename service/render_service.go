@@ -0,0 +1,71 @@
+package service
+
+import (
+	"errors"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+)
+
+// ErrInvalidContentFormat 不支持的内容格式
+var ErrInvalidContentFormat = errors.New("无效的内容格式，仅支持 markdown、html、plain")
+
+// rerenderBatchSize 启动时重新渲染文章的批大小
+const rerenderBatchSize = 100
+
+// renderContent 按内容格式将文章内容渲染为净化后的 HTML
+func renderContent(content, format string) (string, error) {
+	switch format {
+	case model.ContentFormatMarkdown, "":
+		return utils.RenderMarkdown(content)
+	case model.ContentFormatHTML:
+		return utils.SanitizeHTML(content), nil
+	case model.ContentFormatPlain:
+		return utils.RenderPlainText(content), nil
+	default:
+		return "", ErrInvalidContentFormat
+	}
+}
+
+// renderArticle 渲染文章内容并写入 ContentHTML 和 RenderVersion（不保存）
+func renderArticle(article *model.Article) error {
+	html, err := renderContent(article.Content, article.ContentFormat)
+	if err != nil {
+		return err
+	}
+	article.ContentHTML = html
+	article.RenderVersion = utils.RenderVersion
+	return nil
+}
+
+// RerenderStaleArticles 重新渲染渲染结果缺失或渲染规则已过期的文章，服务启动时调用
+func RerenderStaleArticles() error {
+	var lastID uint
+	for {
+		var articles []model.Article
+		result := config.DB.Select("id", "content", "content_format").
+			Where("id > ? AND (render_version IS NULL OR render_version < ?)", lastID, utils.RenderVersion).
+			Order("id ASC").Limit(rerenderBatchSize).Find(&articles)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(articles) == 0 {
+			return nil
+		}
+
+		for _, article := range articles {
+			if err := renderArticle(&article); err != nil {
+				return err
+			}
+			err := config.DB.Model(&model.Article{}).Where("id = ?", article.ID).
+				UpdateColumns(map[string]interface{}{
+					"content_html":   article.ContentHTML,
+					"render_version": article.RenderVersion,
+				}).Error
+			if err != nil {
+				return err
+			}
+			lastID = article.ID
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 修订差异的展示方式
+const (
+	RevisionDiffUnified = "unified" // 正文按行生成统一格式差异
+	RevisionDiffWord    = "word"    // 正文按词生成差异片段
+)
+
+// unifiedDiffContext 统一格式差异中每处修改前后保留的上下文行数
+const unifiedDiffContext = 3
+
+// RevisionDiff 两个修订之间的差异
+type RevisionDiff struct {
+	ArticleID   uint                `json:"article_id"`
+	From        int                 `json:"from"`
+	To          int                 `json:"to"`
+	Mode        string              `json:"mode"`
+	Title       []utils.DiffSegment `json:"title"`             // 标题的词级差异
+	Summary     []utils.DiffSegment `json:"summary"`           // 摘要的词级差异
+	Unified     string              `json:"unified,omitempty"` // 正文的统一格式差异（unified 模式）
+	Words       []utils.DiffSegment `json:"words,omitempty"`   // 正文的词级差异（word 模式）
+	TagsAdded   []uint              `json:"tags_added"`
+	TagsRemoved []uint              `json:"tags_removed"`
+}
+
+// recordRevision 为文章保存一条修订（标题、正文、摘要和标签），并按保留策略清理旧修订
+// article 需已加载标签，editorID 为 0 时记为作者本人
+func recordRevision(tx *gorm.DB, article *model.Article, editorID uint) error {
+	var latest int
+	result := tx.Model(&model.ArticleRevision{}).Where("article_id = ?", article.ID).
+		Select("COALESCE(MAX(revision), 0)").Scan(&latest)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	tagIDs := make([]uint, len(article.Tags))
+	for i, tag := range article.Tags {
+		tagIDs[i] = tag.ID
+	}
+	data, err := json.Marshal(tagIDs)
+	if err != nil {
+		return err
+	}
+
+	if editorID == 0 {
+		editorID = article.UserID
+	}
+	revision := model.ArticleRevision{
+		ArticleID:     article.ID,
+		Revision:      latest + 1,
+		Title:         article.Title,
+		Content:       article.Content,
+		ContentFormat: article.ContentFormat,
+		Summary:       article.Summary,
+		TagIDs:        string(data),
+		EditorID:      editorID,
+	}
+	if err := tx.Create(&revision).Error; err != nil {
+		return err
+	}
+
+	return pruneRevisions(tx, article.ID, revision.Revision)
+}
+
+// ensureRevisionBaseline 文章还没有修订记录时，保存当前内容作为第一个修订
+func ensureRevisionBaseline(tx *gorm.DB, article *model.Article) error {
+	var count int64
+	if err := tx.Model(&model.ArticleRevision{}).Where("article_id = ?", article.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	var current model.Article
+	if err := tx.Preload("Tags").First(&current, article.ID).Error; err != nil {
+		return err
+	}
+	return recordRevision(tx, &current, current.UserID)
+}
+
+// pruneRevisions 按保留策略删除旧修订，最新的修订始终保留
+func pruneRevisions(tx *gorm.DB, articleID uint, latest int) error {
+	conf := config.AppConfig.Revision
+	if conf.KeepLast > 0 {
+		result := tx.Where("article_id = ? AND revision <= ?", articleID, latest-conf.KeepLast).Delete(&model.ArticleRevision{})
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	if conf.KeepDays > 0 {
+		cutoff := time.Now().AddDate(0, 0, -conf.KeepDays)
+		result := tx.Where("article_id = ? AND revision < ? AND created_at < ?", articleID, latest, cutoff).Delete(&model.ArticleRevision{})
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	return nil
+}
+
+// ListArticleRevisions 分页获取文章的修订列表（按修订号倒序，不含正文）
+func ListArticleRevisions(articleID uint, page, pageSize int) ([]model.ArticleRevisionResponse, int64, error) {
+	var revisions []model.ArticleRevision
+	var total int64
+
+	db := config.DB.Model(&model.ArticleRevision{}).Where("article_id = ?", articleID)
+
+	// 计算总数
+	db.Count(&total)
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	result := db.Omit("content").Order("revision DESC").Offset(offset).Limit(pageSize).Find(&revisions)
+
+	// 转换为响应结构
+	responses := make([]model.ArticleRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		responses[i] = *revision.ConvertToArticleRevisionResponse(false)
+	}
+
+	return responses, total, result.Error
+}
+
+// GetArticleRevision 获取文章的指定修订
+func GetArticleRevision(articleID uint, revision int) (*model.ArticleRevision, error) {
+	var rev model.ArticleRevision
+	result := config.DB.Where("article_id = ? AND revision = ?", articleID, revision).First(&rev)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("修订 %d 不存在", revision)
+	}
+	return &rev, result.Error
+}
+
+// GetLatestRevisionNumber 获取文章最新的修订号，没有修订时返回 0
+func GetLatestRevisionNumber(articleID uint) (int, error) {
+	var latest int
+	result := config.DB.Model(&model.ArticleRevision{}).Where("article_id = ?", articleID).
+		Select("COALESCE(MAX(revision), 0)").Scan(&latest)
+	return latest, result.Error
+}
+
+// DiffArticleRevisions 比较文章的两个修订，mode 为 unified 或 word
+func DiffArticleRevisions(articleID uint, from, to int, mode string) (*RevisionDiff, error) {
+	if mode != RevisionDiffUnified && mode != RevisionDiffWord {
+		return nil, errors.New("无效的差异模式")
+	}
+
+	fromRev, err := GetArticleRevision(articleID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := GetArticleRevision(articleID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &RevisionDiff{
+		ArticleID: articleID,
+		From:      from,
+		To:        to,
+		Mode:      mode,
+		Title:     utils.WordDiff(fromRev.Title, toRev.Title),
+		Summary:   utils.WordDiff(fromRev.Summary, toRev.Summary),
+	}
+	if mode == RevisionDiffUnified {
+		diff.Unified = utils.UnifiedDiff(fmt.Sprintf("revision %d", from), fmt.Sprintf("revision %d", to),
+			fromRev.Content, toRev.Content, unifiedDiffContext)
+	} else {
+		diff.Words = utils.WordDiff(fromRev.Content, toRev.Content)
+	}
+
+	fromTags := make(map[uint]bool)
+	for _, id := range fromRev.TagIDList() {
+		fromTags[id] = true
+	}
+	toTags := make(map[uint]bool)
+	diff.TagsAdded = []uint{}
+	for _, id := range toRev.TagIDList() {
+		toTags[id] = true
+		if !fromTags[id] {
+			diff.TagsAdded = append(diff.TagsAdded, id)
+		}
+	}
+	diff.TagsRemoved = []uint{}
+	for _, id := range fromRev.TagIDList() {
+		if !toTags[id] {
+			diff.TagsRemoved = append(diff.TagsRemoved, id)
+		}
+	}
+
+	return diff, nil
+}
+
+// RestoreArticleRevision 将文章恢复为指定修订的标题、正文、摘要和标签，恢复本身也会生成一个新修订
+// 修订中已被删除的标签会被忽略
+func RestoreArticleRevision(articleID uint, revision int, role string, actx AuditContext) error {
+	rev, err := GetArticleRevision(articleID, revision)
+	if err != nil {
+		return err
+	}
+
+	var tags []model.Tag
+	if tagIDs := rev.TagIDList(); len(tagIDs) > 0 {
+		if err := config.DB.Where("id IN ?", tagIDs).Find(&tags).Error; err != nil {
+			return err
+		}
+	}
+
+	articleData := model.Article{
+		Title:         rev.Title,
+		Content:       rev.Content,
+		ContentFormat: rev.ContentFormat,
+		Summary:       rev.Summary,
+		Tags:          tags,
+	}
+	fields := []string{"title", "content", "summary"}
+	// 早期的修订没有记录内容格式，恢复时沿用文章当前的格式
+	if rev.ContentFormat != "" {
+		fields = append(fields, "content_format")
+	}
+	return updateArticle(articleID, &articleData, role, actx, AuditArticleRestore, fields)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"os"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// articleSchedulerLock 定时发布任务使用的数据库锁名称
+const articleSchedulerLock = "article_scheduler"
+
+// ErrInvalidUnpublishAt 定时下线时间无效
+var ErrInvalidUnpublishAt = errors.New("下线时间必须晚于当前时间和发布时间")
+
+// schedulerOwner 当前实例的标识，用于持有调度锁
+var schedulerOwner = newSchedulerOwner()
+
+// newSchedulerOwner 生成实例标识：主机名、进程号加随机串，保证多实例之间不重复
+func newSchedulerOwner() string {
+	hostname, _ := os.Hostname()
+	suffix, _ := utils.GenerateSecureToken(6)
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), suffix)
+}
+
+// validateUnpublishAt 检查下线时间：必须晚于当前时间，设置了发布时间时还必须晚于发布时间
+func validateUnpublishAt(unpublishAt, publishAt *time.Time, now time.Time) error {
+	if unpublishAt == nil {
+		return nil
+	}
+	if !unpublishAt.After(now) || (publishAt != nil && !unpublishAt.After(*publishAt)) {
+		return ErrInvalidUnpublishAt
+	}
+	return nil
+}
+
+// PublishScheduledArticles 发布已到发布时间的定时文章，返回发布的数量
+// 条件更新保证同一篇文章只会被发布一次
+func PublishScheduledArticles(now time.Time) (int, error) {
+	var ids []uint
+	result := config.DB.Model(&model.Article{}).
+		Where("status = ? AND publish_at <= ?", model.ArticleStatusScheduled, now).Pluck("id", &ids)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	published := 0
+	for _, id := range ids {
+		result := config.DB.Model(&model.Article{}).
+			Where("id = ? AND status = ? AND publish_at <= ?", id, model.ArticleStatusScheduled, now).
+			Update("status", model.ArticleStatusPublished)
+		if result.Error != nil {
+			return published, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		published++
+		refreshSearchIndex(id)
+		logAudit(AuditContext{}, AuditArticlePublish, AuditTargetArticle, id,
+			map[string]int{"status": model.ArticleStatusScheduled}, map[string]int{"status": model.ArticleStatusPublished})
+	}
+	return published, nil
+}
+
+// UnpublishExpiredArticles 将已到下线时间的文章归档并清除下线时间，返回下线的数量
+func UnpublishExpiredArticles(now time.Time) (int, error) {
+	var ids []uint
+	result := config.DB.Model(&model.Article{}).
+		Where("status = ? AND unpublish_at <= ?", model.ArticleStatusPublished, now).Pluck("id", &ids)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	unpublished := 0
+	for _, id := range ids {
+		result := config.DB.Model(&model.Article{}).
+			Where("id = ? AND status = ? AND unpublish_at <= ?", id, model.ArticleStatusPublished, now).
+			Updates(map[string]interface{}{"status": model.ArticleStatusArchived, "unpublish_at": nil})
+		if result.Error != nil {
+			return unpublished, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		unpublished++
+		refreshSearchIndex(id)
+		logAudit(AuditContext{}, AuditArticleUnpublish, AuditTargetArticle, id,
+			map[string]int{"status": model.ArticleStatusPublished}, map[string]int{"status": model.ArticleStatusArchived})
+	}
+	return unpublished, nil
+}
+
+// acquireSchedulerLock 尝试获取调度锁，租约为 ttl；锁不存在、已过期或本实例已持有时获取成功
+func acquireSchedulerLock(name string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	lock := model.SchedulerLock{
+		Name:        name,
+		Owner:       schedulerOwner,
+		LockedUntil: now.Add(ttl),
+	}
+	result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&lock)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	result = config.DB.Model(&model.SchedulerLock{}).
+		Where("name = ? AND (locked_until < ? OR owner = ?)", name, now, schedulerOwner).
+		Updates(map[string]interface{}{"owner": schedulerOwner, "locked_until": now.Add(ttl)})
+	return result.RowsAffected > 0, result.Error
+}
+
+// releaseSchedulerLock 释放本实例持有的调度锁
+func releaseSchedulerLock(name string) error {
+	return config.DB.Model(&model.SchedulerLock{}).
+		Where("name = ? AND owner = ?", name, schedulerOwner).
+		Update("locked_until", time.Now()).Error
+}
+
+// runArticleScheduler 获取调度锁后执行一次定时发布和定时下线
+func runArticleScheduler(lease time.Duration) {
+	acquired, err := acquireSchedulerLock(articleSchedulerLock, lease)
+	if err != nil {
+		fmt.Printf("获取调度锁失败: %v\n", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	defer func() {
+		if err := releaseSchedulerLock(articleSchedulerLock); err != nil {
+			fmt.Printf("释放调度锁失败: %v\n", err)
+		}
+	}()
+
+	now := time.Now()
+	if _, err := PublishScheduledArticles(now); err != nil {
+		fmt.Printf("定时发布文章失败: %v\n", err)
+	}
+	if _, err := UnpublishExpiredArticles(now); err != nil {
+		fmt.Printf("定时下线文章失败: %v\n", err)
+	}
+}
+
+// StartArticleScheduler 定期发布和下线到期的文章，应在独立的 goroutine 中运行
+// 多实例部署时通过数据库锁保证同一时间只有一个实例执行
+func StartArticleScheduler(interval time.Duration) {
+	runArticleScheduler(interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		runArticleScheduler(interval)
+	}
+}
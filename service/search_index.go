@@ -0,0 +1,312 @@
+package service
+
+import (
+	"errors"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SearchHit 搜索命中的文章及其相关度
+type SearchHit struct {
+	ArticleID uint    `json:"article_id"`
+	Score     float64 `json:"score"`
+}
+
+// SearchIndex 文章搜索索引
+type SearchIndex interface {
+	// Index 添加或更新文章的搜索文档
+	Index(doc model.ArticleSearchDocument) error
+	// Remove 移除文章的搜索文档，文档不存在时不报错
+	Remove(articleIDs ...uint) error
+	// Search 查找同时包含所有关键词的文章，按相关度从高到低返回第 offset 条起的 limit 条及总数
+	Search(keywords []string, offset, limit int) ([]SearchHit, int64, error)
+}
+
+// 各字段在相关度中的权重
+const (
+	searchWeightTitle    = 3.0
+	searchWeightTag      = 2.0
+	searchWeightCategory = 2.0
+	searchWeightSummary  = 1.5
+	searchWeightBody     = 1.0
+)
+
+// searchTokens 将文本切分为索引词：汉字按相邻两字切分（与 MySQL ngram 分词一致，单个汉字保留原样），
+// 其他字母和数字按连续片段切分并转为小写
+func searchTokens(text string) []string {
+	var tokens []string
+	var word, han []rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	flushHan := func() {
+		if len(han) == 1 {
+			tokens = append(tokens, string(han))
+		}
+		for i := 0; i+1 < len(han); i++ {
+			tokens = append(tokens, string(han[i:i+2]))
+		}
+		han = han[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHan()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+	return tokens
+}
+
+// MemorySearchIndex 基于内存倒排索引的搜索，适用于单实例部署和测试
+// 相关度为各字段加权词频乘以逆文档频率之和
+type MemorySearchIndex struct {
+	postings map[string]map[uint]float64 // 索引词 → 文章ID → 加权词频
+	docTerms map[uint][]string           // 文章ID → 包含的索引词，用于更新和移除
+	mutex    sync.RWMutex
+}
+
+// NewMemorySearchIndex 创建内存搜索索引
+func NewMemorySearchIndex() *MemorySearchIndex {
+	return &MemorySearchIndex{
+		postings: make(map[string]map[uint]float64),
+		docTerms: make(map[uint][]string),
+	}
+}
+
+// Index 添加或更新文章的搜索文档
+func (s *MemorySearchIndex) Index(doc model.ArticleSearchDocument) error {
+	weights := make(map[string]float64)
+	for _, field := range []struct {
+		text   string
+		weight float64
+	}{
+		{doc.Title, searchWeightTitle},
+		{doc.Tags, searchWeightTag},
+		{doc.Category, searchWeightCategory},
+		{doc.Summary, searchWeightSummary},
+		{doc.Body, searchWeightBody},
+	} {
+		for _, token := range searchTokens(field.text) {
+			weights[token] += field.weight
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.remove(doc.ArticleID)
+	terms := make([]string, 0, len(weights))
+	for token, weight := range weights {
+		if s.postings[token] == nil {
+			s.postings[token] = make(map[uint]float64)
+		}
+		s.postings[token][doc.ArticleID] = weight
+		terms = append(terms, token)
+	}
+	s.docTerms[doc.ArticleID] = terms
+	return nil
+}
+
+// Remove 移除文章的搜索文档
+func (s *MemorySearchIndex) Remove(articleIDs ...uint) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, id := range articleIDs {
+		s.remove(id)
+	}
+	return nil
+}
+
+// remove 移除文章的索引词，调用方需持有写锁
+func (s *MemorySearchIndex) remove(articleID uint) {
+	for _, token := range s.docTerms[articleID] {
+		delete(s.postings[token], articleID)
+		if len(s.postings[token]) == 0 {
+			delete(s.postings, token)
+		}
+	}
+	delete(s.docTerms, articleID)
+}
+
+// Search 查找同时包含所有关键词的文章
+func (s *MemorySearchIndex) Search(keywords []string, offset, limit int) ([]SearchHit, int64, error) {
+	var tokens []string
+	for _, keyword := range keywords {
+		tokens = append(tokens, searchTokens(keyword)...)
+	}
+	if len(tokens) == 0 {
+		return nil, 0, nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// 从第一个索引词的文章开始，逐个过滤掉不包含其余索引词的文章
+	scores := make(map[uint]float64)
+	for articleID := range s.postings[tokens[0]] {
+		scores[articleID] = 0
+	}
+	total := float64(len(s.docTerms))
+	for _, token := range tokens {
+		posting := s.postings[token]
+		idf := math.Log(1 + total/float64(len(posting)+1))
+		for articleID := range scores {
+			weight, ok := posting[articleID]
+			if !ok {
+				delete(scores, articleID)
+				continue
+			}
+			scores[articleID] += weight * idf
+		}
+	}
+
+	hits := make([]SearchHit, 0, len(scores))
+	for articleID, score := range scores {
+		hits = append(hits, SearchHit{ArticleID: articleID, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ArticleID > hits[j].ArticleID
+	})
+
+	count := int64(len(hits))
+	if offset >= len(hits) {
+		return []SearchHit{}, count, nil
+	}
+	end := offset + limit
+	if end > len(hits) {
+		end = len(hits)
+	}
+	return hits[offset:end], count, nil
+}
+
+// mysqlSearchIndexes MySQL 搜索使用的全文索引（ngram 分词），名称 → 字段
+var mysqlSearchIndexes = map[string]string{
+	"idx_search_title": "title",
+	"idx_search_all":   "title, summary, body, tags, category",
+}
+
+// MySQLSearchIndex 基于 MySQL FULLTEXT 索引的搜索，使用 ngram 分词支持中文，适用于多实例部署
+// 搜索文档保存在 article_search_documents 表中，标题命中的相关度额外加权
+type MySQLSearchIndex struct {
+	db *gorm.DB
+}
+
+// NewMySQLSearchIndex 创建 MySQL 搜索索引
+func NewMySQLSearchIndex(db *gorm.DB) *MySQLSearchIndex {
+	return &MySQLSearchIndex{db: db}
+}
+
+// EnsureSchema 创建缺少的全文索引（AutoMigrate 不支持指定 ngram 分词）
+func (s *MySQLSearchIndex) EnsureSchema() error {
+	for name, columns := range mysqlSearchIndexes {
+		var count int64
+		err := s.db.Raw("SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?",
+			model.ArticleSearchDocument{}.TableName(), name).Scan(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		err = s.db.Exec("ALTER TABLE " + model.ArticleSearchDocument{}.TableName() +
+			" ADD FULLTEXT INDEX " + name + " (" + columns + ") WITH PARSER ngram").Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Index 添加或更新文章的搜索文档
+func (s *MySQLSearchIndex) Index(doc model.ArticleSearchDocument) error {
+	return s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&doc).Error
+}
+
+// Remove 移除文章的搜索文档
+func (s *MySQLSearchIndex) Remove(articleIDs ...uint) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	return s.db.Where("article_id IN ?", articleIDs).Delete(&model.ArticleSearchDocument{}).Error
+}
+
+// Search 使用布尔模式查找同时包含所有关键词的文章（ngram 分词下每个关键词按短语匹配）
+func (s *MySQLSearchIndex) Search(keywords []string, offset, limit int) ([]SearchHit, int64, error) {
+	terms := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		// 去除布尔模式的运算符
+		keyword = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`+-<>()~*"@`, r) {
+				return -1
+			}
+			return r
+		}, keyword)
+		if keyword != "" {
+			terms = append(terms, "+"+keyword)
+		}
+	}
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+	against := strings.Join(terms, " ")
+	match := "MATCH(title, summary, body, tags, category) AGAINST(? IN BOOLEAN MODE)"
+
+	var total int64
+	if err := s.db.Model(&model.ArticleSearchDocument{}).Where(match, against).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var hits []SearchHit
+	err := s.db.Model(&model.ArticleSearchDocument{}).
+		Select("article_id, MATCH(title) AGAINST(? IN BOOLEAN MODE) * ? + "+match+" AS score", against, searchWeightTitle, against).
+		Where(match, against).
+		Order("score DESC, article_id DESC").Offset(offset).Limit(limit).Scan(&hits).Error
+	return hits, total, err
+}
+
+// searchIndex 全局搜索索引，由 InitSearchIndex 根据配置初始化
+var searchIndex SearchIndex = NewMemorySearchIndex()
+
+// InitSearchIndex 根据配置初始化搜索索引并补全缺少的搜索文档，需在数据库初始化之后调用
+func InitSearchIndex() error {
+	switch config.AppConfig.Search.Backend {
+	case "", "mysql":
+		index := NewMySQLSearchIndex(config.DB)
+		if err := index.EnsureSchema(); err != nil {
+			return err
+		}
+		searchIndex = index
+		return indexPublishedArticles(true)
+	case "memory":
+		searchIndex = NewMemorySearchIndex()
+		return indexPublishedArticles(false)
+	default:
+		return errors.New("不支持的搜索索引: " + config.AppConfig.Search.Backend)
+	}
+}
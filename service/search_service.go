@@ -0,0 +1,222 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// ErrEmptySearchQuery 搜索关键词为空
+var ErrEmptySearchQuery = errors.New("请输入搜索关键词")
+
+const (
+	// maxSearchKeywords 单次搜索最多使用的关键词数
+	maxSearchKeywords = 10
+	// searchIndexBatchSize 启动时补全搜索文档的批大小
+	searchIndexBatchSize = 100
+	// searchSnippetLength 正文摘录的长度（字符数）
+	searchSnippetLength = 160
+)
+
+// ParseSearchKeywords 按空白拆分搜索词，去除重复，最多保留 maxSearchKeywords 个
+func ParseSearchKeywords(query string) []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, keyword := range strings.Fields(strings.ToLower(query)) {
+		if seen[keyword] {
+			continue
+		}
+		seen[keyword] = true
+		keywords = append(keywords, keyword)
+		if len(keywords) == maxSearchKeywords {
+			break
+		}
+	}
+	return keywords
+}
+
+// SearchArticles 全文搜索已发布的文章（标题、摘要、正文、标签和分类名称），按相关度排序并返回高亮摘录
+func SearchArticles(query string, page, pageSize int) ([]model.SearchResultResponse, int64, error) {
+	keywords := ParseSearchKeywords(query)
+	if len(keywords) == 0 {
+		return nil, 0, ErrEmptySearchQuery
+	}
+
+	offset := (page - 1) * pageSize
+	hits, total, err := searchIndex.Search(keywords, offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ArticleID
+	}
+	var articles []model.Article
+	result := publishedArticlesQuery().Where("id IN ?", ids).Find(&articles)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	articleMap := make(map[uint]model.Article, len(articles))
+	for _, article := range articles {
+		articleMap[article.ID] = article
+	}
+
+	// 按相关度顺序返回，索引中已失效的文章跳过
+	results := make([]model.SearchResultResponse, 0, len(hits))
+	for _, hit := range hits {
+		article, ok := articleMap[hit.ArticleID]
+		if !ok {
+			continue
+		}
+		results = append(results, model.SearchResultResponse{
+			ArticleResponse: *article.ConvertToArticleResponse(),
+			Score:           hit.Score,
+			Highlight: model.SearchHighlight{
+				Title:   highlightKeywords(article.Title, keywords, 0),
+				Summary: highlightKeywords(article.Summary, keywords, 0),
+				Content: highlightKeywords(utils.HTMLToText(article.ContentHTML), keywords, searchSnippetLength),
+			},
+		})
+	}
+	return results, total, nil
+}
+
+// highlightKeywords 转义文本并用 <mark> 标出关键词（不区分大小写）
+// maxLength 大于 0 时只截取第一个关键词附近的 maxLength 个字符作为摘录
+func highlightKeywords(text string, keywords []string, maxLength int) string {
+	runes := []rune(text)
+	lower := []rune(strings.Map(unicode.ToLower, text))
+
+	// 标记每个字符是否属于某个关键词
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, keyword := range keywords {
+		k := []rune(keyword)
+		for i := 0; i+len(k) <= len(lower); i++ {
+			if string(lower[i:i+len(k)]) != keyword {
+				continue
+			}
+			for j := i; j < i+len(k); j++ {
+				marked[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+
+	start, end := 0, len(runes)
+	if maxLength > 0 && len(runes) > maxLength {
+		// 关键词前保留四分之一的长度作为上下文
+		if first > maxLength/4 {
+			start = first - maxLength/4
+		}
+		end = start + maxLength
+		if end > len(runes) {
+			end = len(runes)
+			start = end - maxLength
+		}
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+		segment := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			sb.WriteString("<mark>" + segment + "</mark>")
+		} else {
+			sb.WriteString(segment)
+		}
+		i = j
+	}
+	if end < len(runes) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+// buildSearchDocument 根据文章（需预加载分类和标签）生成搜索文档
+func buildSearchDocument(article *model.Article) model.ArticleSearchDocument {
+	tags := make([]string, len(article.Tags))
+	for i, tag := range article.Tags {
+		tags[i] = tag.Name
+	}
+	return model.ArticleSearchDocument{
+		ArticleID: article.ID,
+		Title:     article.Title,
+		Summary:   article.Summary,
+		Body:      utils.HTMLToText(article.ContentHTML),
+		Tags:      strings.Join(tags, " "),
+		Category:  article.Category.Name,
+	}
+}
+
+// refreshSearchIndex 按文章当前内容更新搜索索引：已发布的文章写入索引，其余（包括已删除的）移除。
+// 在数据修改提交之后调用，失败时只记录日志，下次启动时会补全缺少的搜索文档
+func refreshSearchIndex(articleIDs ...uint) {
+	if len(articleIDs) == 0 {
+		return
+	}
+
+	var articles []model.Article
+	if err := publishedArticlesQuery().Where("id IN ?", articleIDs).Find(&articles).Error; err != nil {
+		fmt.Printf("更新搜索索引失败: %v\n", err)
+		return
+	}
+
+	published := make(map[uint]bool, len(articles))
+	for _, article := range articles {
+		published[article.ID] = true
+		if err := searchIndex.Index(buildSearchDocument(&article)); err != nil {
+			fmt.Printf("更新搜索索引失败: %v\n", err)
+		}
+	}
+
+	var removed []uint
+	for _, id := range articleIDs {
+		if !published[id] {
+			removed = append(removed, id)
+		}
+	}
+	if err := searchIndex.Remove(removed...); err != nil {
+		fmt.Printf("更新搜索索引失败: %v\n", err)
+	}
+}
+
+// indexPublishedArticles 将已发布的文章写入搜索索引，onlyMissing 为 true 时只处理还没有搜索文档的文章
+func indexPublishedArticles(onlyMissing bool) error {
+	var lastID uint
+	for {
+		db := publishedArticlesQuery().Where("id > ?", lastID)
+		if onlyMissing {
+			db = db.Where("id NOT IN (?)", config.DB.Model(&model.ArticleSearchDocument{}).Select("article_id"))
+		}
+
+		var articles []model.Article
+		if err := db.Order("id ASC").Limit(searchIndexBatchSize).Find(&articles).Error; err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			return nil
+		}
+
+		for _, article := range articles {
+			if err := searchIndex.Index(buildSearchDocument(&article)); err != nil {
+				return err
+			}
+			lastID = article.ID
+		}
+	}
+}
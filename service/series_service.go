@@ -0,0 +1,433 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSeriesNotFound        = errors.New("系列不存在")
+	ErrArticleInOtherSeries  = errors.New("文章已属于其他系列")
+	ErrInvalidSeriesArticles = errors.New("无效的系列文章列表")
+	ErrArticleNotInSeries    = errors.New("文章不在该系列中")
+)
+
+// seriesPart 系列中的一篇文章及其状态
+type seriesPart struct {
+	ArticleID uint
+	Title     string
+	Slug      string
+	Summary   string
+	Status    int
+}
+
+// seriesParts 按顺序查询系列中的文章
+func seriesParts(db *gorm.DB, seriesID uint) ([]seriesPart, error) {
+	var parts []seriesPart
+	result := db.Table("series_articles").
+		Select("series_articles.article_id, articles.title, articles.slug, articles.summary, articles.status").
+		Joins("JOIN articles ON articles.id = series_articles.article_id").
+		Where("series_articles.series_id = ?", seriesID).
+		Order("series_articles.position ASC").
+		Scan(&parts)
+	return parts, result.Error
+}
+
+// toResponse 转换为系列中的一篇，position 为展示顺序
+func (p seriesPart) toResponse(position int) *model.SeriesPartResponse {
+	return &model.SeriesPartResponse{
+		ID:       p.ArticleID,
+		Title:    p.Title,
+		Slug:     p.Slug,
+		Summary:  p.Summary,
+		State:    model.ArticleStatusName(p.Status),
+		Position: position,
+	}
+}
+
+// seriesArticleIDs 将文章ID列表转换为审计记录中的字符串（数组不参与变更对比）
+func seriesArticleIDs(ids []uint) map[string]interface{} {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return map[string]interface{}{"article_ids": strings.Join(values, ",")}
+}
+
+// CreateSeries 创建系列，articleIDs 为按顺序排列的文章，创建者必须有权编辑其中的每一篇
+func CreateSeries(series *model.Series, articleIDs []uint, role string, actx AuditContext) error {
+	if err := checkSeriesArticles(0, articleIDs, nil, series.UserID, role); err != nil {
+		return err
+	}
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Create(series).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := writeSeriesArticles(tx, series.ID, articleIDs); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordAudit(tx, actx, AuditSeriesCreate, AuditTargetSeries, series.ID, nil, series); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if len(articleIDs) > 0 {
+		if err := recordAudit(tx, actx, AuditSeriesArticles, AuditTargetSeries, series.ID, nil, seriesArticleIDs(articleIDs)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// GetSeriesByID 获取系列及其中按顺序排列的文章
+// 未发布的文章只对系列创建者和拥有 PermArticleReview 权限的角色列出
+func GetSeriesByID(id, viewerID uint, role string) (*model.SeriesResponse, error) {
+	var series model.Series
+	result := config.DB.Preload("User").First(&series, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrSeriesNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	parts, err := seriesParts(config.DB, id)
+	if err != nil {
+		return nil, err
+	}
+
+	showAll := series.UserID == viewerID || model.HasPermission(role, model.PermArticleReview)
+	response := series.ConvertToSeriesResponse()
+	response.Articles = make([]model.SeriesPartResponse, 0, len(parts))
+	for _, part := range parts {
+		if part.Status == model.ArticleStatusPublished {
+			response.ArticleCount++
+		} else if !showAll {
+			continue
+		}
+		response.Articles = append(response.Articles, *part.toResponse(len(response.Articles) + 1))
+	}
+	return response, nil
+}
+
+// GetAllSeries 分页获取系列列表（按创建时间倒序），userID 不为 0 时只列出该用户创建的系列
+func GetAllSeries(userID uint, req PageRequest) ([]model.SeriesResponse, *PageResult, error) {
+	db := config.DB.Model(&model.Series{}).Preload("User")
+	scope := "series"
+	if userID != 0 {
+		db = db.Where("user_id = ?", userID)
+		scope = fmt.Sprintf("series:user:%d", userID)
+	}
+
+	spec := keysetSpec{
+		Scope: scope,
+		Columns: []keysetColumn{
+			{Name: "series.created_at", Desc: true, Kind: keysetTime},
+			{Name: "series.id", Desc: true, Kind: keysetInt},
+		},
+		Values: func(item interface{}) []interface{} {
+			series := item.(*model.Series)
+			return []interface{}{cursorTime(series.CreatedAt), series.ID}
+		},
+	}
+	var seriesList []model.Series
+	page, err := findPage(db, spec, req, &seriesList)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 统计每个系列中已发布的文章数
+	ids := make([]uint, len(seriesList))
+	for i, series := range seriesList {
+		ids[i] = series.ID
+	}
+	var counts []struct {
+		SeriesID uint
+		Count    int
+	}
+	if len(ids) > 0 {
+		result := config.DB.Table("series_articles").
+			Select("series_articles.series_id, COUNT(*) AS count").
+			Joins("JOIN articles ON articles.id = series_articles.article_id").
+			Where("series_articles.series_id IN ? AND articles.status = ?", ids, model.ArticleStatusPublished).
+			Group("series_articles.series_id").
+			Scan(&counts)
+		if result.Error != nil {
+			return nil, nil, result.Error
+		}
+	}
+	countMap := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		countMap[count.SeriesID] = count.Count
+	}
+
+	responses := make([]model.SeriesResponse, len(seriesList))
+	for i, series := range seriesList {
+		responses[i] = *series.ConvertToSeriesResponse()
+		responses[i].ArticleCount = countMap[series.ID]
+	}
+	return responses, page, nil
+}
+
+// UpdateSeries 更新系列的标题、简介和封面
+func UpdateSeries(id uint, seriesData *model.Series, actx AuditContext) error {
+	var existingSeries model.Series
+	result := config.DB.First(&existingSeries, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return ErrSeriesNotFound
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	before := existingSeries
+
+	existingSeries.Title = seriesData.Title
+	existingSeries.Description = seriesData.Description
+	if seriesData.Cover != "" {
+		existingSeries.Cover = seriesData.Cover
+	}
+
+	result = config.DB.Model(&existingSeries).Select("title", "description", "cover").Updates(&existingSeries)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	logAudit(actx, AuditSeriesUpdate, AuditTargetSeries, id, before, existingSeries)
+	return nil
+}
+
+// DeleteSeries 删除系列，系列中的文章保留，只解除关联
+func DeleteSeries(id uint, actx AuditContext) error {
+	var series model.Series
+	result := config.DB.First(&series, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return ErrSeriesNotFound
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("series_id = ?", id).Delete(&model.SeriesArticle{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&series).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordAudit(tx, actx, AuditSeriesDelete, AuditTargetSeries, id, series, nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// SetSeriesArticles 按给定顺序替换系列中的文章，用于调整顺序以及批量添加、移除文章
+// 新加入的文章要求当前用户有权编辑；移出系列的文章不做检查
+func SetSeriesArticles(id uint, articleIDs []uint, userID uint, role string, actx AuditContext) error {
+	var current []uint
+	result := config.DB.Model(&model.SeriesArticle{}).Where("series_id = ?", id).Order("position ASC").Pluck("article_id", &current)
+	if result.Error != nil {
+		return result.Error
+	}
+	if err := checkSeriesArticles(id, articleIDs, current, userID, role); err != nil {
+		return err
+	}
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("series_id = ?", id).Delete(&model.SeriesArticle{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := writeSeriesArticles(tx, id, articleIDs); err != nil {
+		tx.Rollback()
+		return err
+	}
+	// 更新系列的修改时间
+	if err := tx.Model(&model.Series{}).Where("id = ?", id).Update("updated_at", time.Now()).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordAudit(tx, actx, AuditSeriesArticles, AuditTargetSeries, id, seriesArticleIDs(current), seriesArticleIDs(articleIDs)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// AddSeriesArticle 将文章添加到系列末尾
+func AddSeriesArticle(id, articleID, userID uint, role string, actx AuditContext) error {
+	var current []uint
+	result := config.DB.Model(&model.SeriesArticle{}).Where("series_id = ?", id).Order("position ASC").Pluck("article_id", &current)
+	if result.Error != nil {
+		return result.Error
+	}
+	for _, existing := range current {
+		if existing == articleID {
+			return nil
+		}
+	}
+	return SetSeriesArticles(id, append(current, articleID), userID, role, actx)
+}
+
+// RemoveSeriesArticle 将文章移出系列，其余文章保持原有顺序
+func RemoveSeriesArticle(id, articleID, userID uint, role string, actx AuditContext) error {
+	var current []uint
+	result := config.DB.Model(&model.SeriesArticle{}).Where("series_id = ?", id).Order("position ASC").Pluck("article_id", &current)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	remaining := make([]uint, 0, len(current))
+	for _, existing := range current {
+		if existing != articleID {
+			remaining = append(remaining, existing)
+		}
+	}
+	if len(remaining) == len(current) {
+		return ErrArticleNotInSeries
+	}
+	return SetSeriesArticles(id, remaining, userID, role, actx)
+}
+
+// checkSeriesArticles 检查系列的文章列表：不能重复，文章必须存在且不属于其他系列，
+// 不在 current 中的文章（新加入的）要求用户有权编辑
+func checkSeriesArticles(seriesID uint, articleIDs, current []uint, userID uint, role string) error {
+	seen := make(map[uint]bool, len(articleIDs))
+	for _, articleID := range articleIDs {
+		if seen[articleID] {
+			return fmt.Errorf("%w：文章 %d 重复", ErrInvalidSeriesArticles, articleID)
+		}
+		seen[articleID] = true
+	}
+	if len(articleIDs) == 0 {
+		return nil
+	}
+
+	var existing []uint
+	if err := config.DB.Model(&model.Article{}).Where("id IN ?", articleIDs).Pluck("id", &existing).Error; err != nil {
+		return err
+	}
+	found := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+
+	inSeries := make(map[uint]bool, len(current))
+	for _, id := range current {
+		inSeries[id] = true
+	}
+	for _, articleID := range articleIDs {
+		if !found[articleID] {
+			return fmt.Errorf("%w：文章 %d 不存在", ErrInvalidSeriesArticles, articleID)
+		}
+		if inSeries[articleID] {
+			continue
+		}
+		if err := CheckArticlePermission(articleID, userID, role); err != nil {
+			return err
+		}
+	}
+
+	var count int64
+	result := config.DB.Model(&model.SeriesArticle{}).Where("article_id IN ? AND series_id <> ?", articleIDs, seriesID).Count(&count)
+	if result.Error != nil {
+		return result.Error
+	}
+	if count > 0 {
+		return ErrArticleInOtherSeries
+	}
+	return nil
+}
+
+// writeSeriesArticles 按顺序写入系列中的文章，位置从 1 开始
+func writeSeriesArticles(tx *gorm.DB, seriesID uint, articleIDs []uint) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	rows := make([]model.SeriesArticle, len(articleIDs))
+	for i, articleID := range articleIDs {
+		rows[i] = model.SeriesArticle{ArticleID: articleID, SeriesID: seriesID, Position: i + 1}
+	}
+	return tx.Create(&rows).Error
+}
+
+// attachArticleSeries 填充文章所属的系列以及前一篇、后一篇
+// 序号和总数只计算已发布的文章（以及当前文章本身），前后篇只链接已发布的文章
+func attachArticleSeries(response *model.ArticleResponse) error {
+	var link model.SeriesArticle
+	result := config.DB.Where("article_id = ?", response.ID).Limit(1).Find(&link)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+
+	var series model.Series
+	if err := config.DB.Select("id", "title").First(&series, link.SeriesID).Error; err != nil {
+		return err
+	}
+	parts, err := seriesParts(config.DB, series.ID)
+	if err != nil {
+		return err
+	}
+
+	visible := make([]seriesPart, 0, len(parts))
+	current := -1
+	for _, part := range parts {
+		if part.ArticleID == response.ID {
+			current = len(visible)
+		} else if part.Status != model.ArticleStatusPublished {
+			continue
+		}
+		visible = append(visible, part)
+	}
+
+	info := &model.ArticleSeries{
+		ID:       series.ID,
+		Title:    series.Title,
+		Position: current + 1,
+		Total:    len(visible),
+	}
+	if current > 0 {
+		info.Prev = visible[current-1].toResponse(current)
+	}
+	if current >= 0 && current+1 < len(visible) {
+		info.Next = visible[current+1].toResponse(current + 2)
+	}
+	response.Series = info
+	return nil
+}
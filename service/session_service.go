@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// sessionTouchInterval 会话最近使用时间的最小更新间隔，避免每个请求都写库
+const sessionTouchInterval = time.Minute
+
+// ListUserSessions 获取用户当前有效的登录会话
+func ListUserSessions(userID, currentSessionID uint) ([]model.SessionResponse, error) {
+	var sessions []model.Session
+	result := config.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions)
+
+	// 转换为响应结构
+	responses := make([]model.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = *session.ConvertToSessionResponse(currentSessionID)
+	}
+
+	return responses, result.Error
+}
+
+// RevokeSession 注销用户的指定会话
+func RevokeSession(userID, sessionID uint) error {
+	var session model.Session
+	result := config.DB.Where("id = ? AND user_id = ?", sessionID, userID).First(&session)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return errors.New("会话不存在")
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return RevokeTokenFamily(session.FamilyID, "session_revoked")
+}
+
+// RevokeAllSessions 注销用户的全部会话（在所有设备上登出）
+func RevokeAllSessions(userID uint) error {
+	return RevokeAllUserTokens(userID, "logout_all")
+}
+
+// TouchSession 更新会话的最近使用时间和IP
+func TouchSession(sessionID uint, clientIP string) error {
+	now := time.Now()
+	result := config.DB.Model(&model.Session{}).
+		Where("id = ? AND last_used_at < ?", sessionID, now.Add(-sessionTouchInterval)).
+		Updates(map[string]interface{}{
+			"last_used_at": now,
+			"ip":           clientIP,
+		})
+	return result.Error
+}
+
+// truncateString 按字符截断字符串
+func truncateString(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen])
+}
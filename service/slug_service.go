@@ -0,0 +1,208 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// slug 所属的对象类型
+const (
+	SlugTargetArticle  = "article"
+	SlugTargetCategory = "category"
+	SlugTargetTag      = "tag"
+)
+
+// slugTables 对象类型对应的数据表
+var slugTables = map[string]string{
+	SlugTargetArticle:  model.Article{}.TableName(),
+	SlugTargetCategory: model.Category{}.TableName(),
+	SlugTargetTag:      model.Tag{}.TableName(),
+}
+
+// maxSlugSuffix slug 冲突时追加的最大序号
+const maxSlugSuffix = 1000
+
+// ErrInvalidSlug 指定的 slug 不包含任何有效字符
+var ErrInvalidSlug = errors.New("无效的slug")
+
+// newSlug 为对象生成唯一的 slug：指定了 requested 时以其为准，否则根据 source（标题或名称）生成，
+// 与其他对象的当前 slug 或历史 slug 冲突时追加 -2、-3 等序号
+func newSlug(db *gorm.DB, targetType, requested, source string, excludeID uint) (string, error) {
+	var base string
+	if requested != "" {
+		base = utils.Slugify(requested)
+		if base == "" {
+			return "", ErrInvalidSlug
+		}
+	} else {
+		base = utils.Slugify(source)
+		if base == "" {
+			base = targetType
+		}
+	}
+
+	for i := 1; i <= maxSlugSuffix; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		taken, err := slugTaken(db, targetType, candidate, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+	return "", errors.New("无法生成唯一的slug")
+}
+
+// slugTaken 检查 slug 是否已被其他对象使用（包括其他对象的历史 slug）
+func slugTaken(db *gorm.DB, targetType, slug string, excludeID uint) (bool, error) {
+	var count int64
+	result := db.Table(slugTables[targetType]).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count)
+	if result.Error != nil || count > 0 {
+		return count > 0, result.Error
+	}
+
+	result = db.Model(&model.SlugHistory{}).
+		Where("target_type = ? AND slug = ? AND target_id <> ?", targetType, slug, excludeID).Count(&count)
+	return count > 0, result.Error
+}
+
+// updateSlug 计算对象更新后的 slug：指定了 requested 时使用指定值，否则仅在标题或名称变化时重新生成。
+// slug 变化时旧 slug 写入历史记录，旧地址会跳转到新地址
+func updateSlug(db *gorm.DB, targetType string, id uint, currentSlug, requested, newSource, oldSource string) (string, error) {
+	if requested == "" && (newSource == "" || newSource == oldSource) && currentSlug != "" {
+		return currentSlug, nil
+	}
+	if requested != "" && utils.Slugify(requested) == currentSlug {
+		return currentSlug, nil
+	}
+
+	source := newSource
+	if source == "" {
+		source = oldSource
+	}
+	slug, err := newSlug(db, targetType, requested, source, id)
+	if err != nil {
+		return "", err
+	}
+	if slug == currentSlug {
+		return slug, nil
+	}
+
+	if currentSlug != "" {
+		history := model.SlugHistory{
+			TargetType: targetType,
+			TargetID:   id,
+			Slug:       currentSlug,
+		}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&history).Error; err != nil {
+			return "", err
+		}
+	}
+	// 改回曾经使用过的 slug 时，移除对应的历史记录
+	if err := db.Where("target_type = ? AND target_id = ? AND slug = ?", targetType, id, slug).
+		Delete(&model.SlugHistory{}).Error; err != nil {
+		return "", err
+	}
+	return slug, nil
+}
+
+// deleteSlugHistory 删除对象的历史 slug，对象被删除时调用
+func deleteSlugHistory(db *gorm.DB, targetType string, ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.Where("target_type = ? AND target_id IN ?", targetType, ids).Delete(&model.SlugHistory{}).Error
+}
+
+// ResolveSlug 根据 slug 查找对象，返回对象ID和当前 slug；
+// 通过历史 slug 找到时，当前 slug 与传入的不同，调用方应跳转到新地址
+func ResolveSlug(targetType, slug string) (uint, string, error) {
+	table, ok := slugTables[targetType]
+	if !ok {
+		return 0, "", errors.New("未知的对象类型")
+	}
+
+	var current struct {
+		ID   uint
+		Slug string
+	}
+	result := config.DB.Table(table).Select("id", "slug").Where("slug = ?", slug).Limit(1).Scan(&current)
+	if result.Error != nil {
+		return 0, "", result.Error
+	}
+	if current.ID != 0 {
+		return current.ID, current.Slug, nil
+	}
+
+	var history model.SlugHistory
+	result = config.DB.Where("target_type = ? AND slug = ?", targetType, slug).Limit(1).Find(&history)
+	if result.Error != nil {
+		return 0, "", result.Error
+	}
+	if history.ID == 0 {
+		return 0, "", errors.New("内容不存在")
+	}
+
+	result = config.DB.Table(table).Select("id", "slug").Where("id = ?", history.TargetID).Limit(1).Scan(&current)
+	if result.Error != nil {
+		return 0, "", result.Error
+	}
+	if current.ID == 0 {
+		return 0, "", errors.New("内容不存在")
+	}
+	return current.ID, current.Slug, nil
+}
+
+// BackfillSlugs 为没有 slug 的文章、分类和标签生成 slug，服务启动时调用
+func BackfillSlugs() error {
+	var articles []model.Article
+	if err := config.DB.Select("id", "title").Where("slug IS NULL OR slug = ''").Find(&articles).Error; err != nil {
+		return err
+	}
+	for _, article := range articles {
+		if err := backfillSlug(SlugTargetArticle, article.ID, article.Title); err != nil {
+			return err
+		}
+	}
+
+	var categories []model.Category
+	if err := config.DB.Select("id", "name").Where("slug IS NULL OR slug = ''").Find(&categories).Error; err != nil {
+		return err
+	}
+	for _, category := range categories {
+		if err := backfillSlug(SlugTargetCategory, category.ID, category.Name); err != nil {
+			return err
+		}
+	}
+
+	var tags []model.Tag
+	if err := config.DB.Select("id", "name").Where("slug IS NULL OR slug = ''").Find(&tags).Error; err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if err := backfillSlug(SlugTargetTag, tag.ID, tag.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillSlug 为单个对象生成并保存 slug
+func backfillSlug(targetType string, id uint, source string) error {
+	slug, err := newSlug(config.DB, targetType, "", source, id)
+	if err != nil {
+		return err
+	}
+	return config.DB.Table(slugTables[targetType]).Where("id = ?", id).UpdateColumn("slug", slug).Error
+}
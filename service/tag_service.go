@@ -8,15 +8,27 @@ import (
 )
 
 // CreateTag 创建标签
-func CreateTag(tag *model.Tag) error {
+func CreateTag(tag *model.Tag, actx AuditContext) error {
+	// 未指定 slug 时根据名称生成
+	slug, err := newSlug(config.DB, SlugTargetTag, tag.Slug, tag.Name, 0)
+	if err != nil {
+		return err
+	}
+	tag.Slug = slug
+
 	result := config.DB.Create(tag)
-	return result.Error
+	if result.Error != nil {
+		return result.Error
+	}
+
+	logAudit(actx, AuditTagCreate, AuditTargetTag, tag.ID, nil, tag)
+	return nil
 }
 
-// GetTagByID 根据ID获取标签
+// GetTagByID 根据ID获取标签（只返回已发布的文章）
 func GetTagByID(id uint) (*model.Tag, error) {
 	var tag model.Tag
-	result := config.DB.Preload("Articles").First(&tag, id)
+	result := config.DB.Preload("Articles", "status = ?", model.ArticleStatusPublished).First(&tag, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, errors.New("标签不存在")
 	}
@@ -26,24 +38,47 @@ func GetTagByID(id uint) (*model.Tag, error) {
 // GetAllTags 获取所有标签
 func GetAllTags() ([]model.Tag, error) {
 	var tags []model.Tag
-	result := config.DB.Preload("Articles").Find(&tags)
+	result := config.DB.Preload("Articles", "status = ?", model.ArticleStatusPublished).Find(&tags)
 	return tags, result.Error
 }
 
 // UpdateTag 更新标签
-func UpdateTag(id uint, tagData *model.Tag) error {
+func UpdateTag(id uint, tagData *model.Tag, actx AuditContext) error {
 	var existingTag model.Tag
 	result := config.DB.First(&existingTag, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return errors.New("标签不存在")
 	}
 
+	before := existingTag
+
+	// 名称或 slug 变化时更新 slug，旧 slug 保留用于跳转
+	slug, err := updateSlug(config.DB, SlugTargetTag, id, existingTag.Slug, tagData.Slug, tagData.Name, existingTag.Name)
+	if err != nil {
+		return err
+	}
+	tagData.Slug = slug
+
 	result = config.DB.Model(&existingTag).Updates(tagData)
-	return result.Error
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// 标签名称写入了搜索文档，需要更新使用该标签的文章
+	if existingTag.Name != before.Name {
+		var articleIDs []uint
+		if err := config.DB.Model(&model.ArticleTag{}).Where("tag_id = ?", id).Pluck("article_id", &articleIDs).Error; err != nil {
+			return err
+		}
+		refreshSearchIndex(articleIDs...)
+	}
+
+	logAudit(actx, AuditTagUpdate, AuditTargetTag, id, before, existingTag)
+	return nil
 }
 
 // DeleteTag 删除标签
-func DeleteTag(id uint) error {
+func DeleteTag(id uint, actx AuditContext) error {
 	var tag model.Tag
 	result := config.DB.First(&tag, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -51,19 +86,39 @@ func DeleteTag(id uint) error {
 	}
 
 	// 删除标签前，需要处理相关的文章（可以将文章的标签关联移除）
+	var articleIDs []uint
+	if err := config.DB.Model(&model.ArticleTag{}).Where("tag_id = ?", id).Pluck("article_id", &articleIDs).Error; err != nil {
+		return err
+	}
 	deleteResult := config.DB.Where("tag_id = ?", id).Delete(&model.ArticleTag{})
 	if deleteResult.Error != nil {
 		return deleteResult.Error
 	}
+	refreshSearchIndex(articleIDs...)
+
+	// 同时移除用户对该标签的关注
+	deleteResult = config.DB.Where("tag_id = ?", id).Delete(&model.TagFollow{})
+	if deleteResult.Error != nil {
+		return deleteResult.Error
+	}
 
 	// 删除标签本身
 	result = config.DB.Delete(&tag)
-	return result.Error
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if err := deleteSlugHistory(config.DB, SlugTargetTag, id); err != nil {
+		return err
+	}
+
+	logAudit(actx, AuditTagDelete, AuditTargetTag, id, tag, nil)
+	return nil
 }
 
 // GetTagsByStatus 根据状态获取标签
 func GetTagsByStatus(status int) ([]model.Tag, error) {
 	var tags []model.Tag
-	result := config.DB.Where("status = ?", status).Preload("Articles").Find(&tags)
+	result := config.DB.Where("status = ?", status).Preload("Articles", "status = ?", model.ArticleStatusPublished).Find(&tags)
 	return tags, result.Error
 }
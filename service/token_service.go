@@ -0,0 +1,368 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 刷新令牌相关错误
+var (
+	ErrInvalidRefreshToken = errors.New("无效的刷新令牌")
+	ErrRefreshTokenReused  = errors.New("刷新令牌已被使用，登录已失效，请重新登录")
+)
+
+// TokenPair 访问令牌与刷新令牌
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // 访问令牌有效期（秒）
+}
+
+// accessTokenTTL 访问令牌有效期
+func accessTokenTTL() time.Duration {
+	return config.ParseDurationOrDefault(config.AppConfig.App.AccessTokenExpire, 15*time.Minute)
+}
+
+// refreshTokenTTL 刷新令牌有效期
+func refreshTokenTTL() time.Duration {
+	return config.ParseDurationOrDefault(config.AppConfig.App.RefreshTokenExpire, 7*24*time.Hour)
+}
+
+// IssueTokenPair 为用户签发令牌对，每次登录开启一个新的会话（令牌族）
+func IssueTokenPair(user *model.User, clientIP, userAgent string) (*TokenPair, error) {
+	familyID, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	// 开始事务
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	session := model.Session{
+		UserID:     user.ID,
+		FamilyID:   familyID,
+		UserAgent:  truncateString(userAgent, 255),
+		IP:         clientIP,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(refreshTokenTTL()),
+	}
+	if err := tx.Create(&session).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	pair, err := issueTokenPair(tx, user, familyID, session.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// issueTokenPair 在指定令牌族中签发令牌对
+func issueTokenPair(db *gorm.DB, user *model.User, familyID string, sessionID uint) (*TokenPair, error) {
+	jti, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := GenerateToken(user, jti, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	record := model.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: utils.HashToken(refreshToken),
+		AccessJTI: jti,
+		ExpiresAt: time.Now().Add(refreshTokenTTL()),
+	}
+	if err := db.Create(&record).Error; err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(accessTokenTTL().Seconds()),
+	}, nil
+}
+
+// checkRefreshToken 检查刷新令牌能否用于轮换：已轮换或已作废的令牌返回 ErrRefreshTokenReused，
+// 调用方需要作废整个令牌族；过期的令牌返回 ErrInvalidRefreshToken
+func checkRefreshToken(record *model.RefreshToken, now time.Time) error {
+	if record.UsedAt != nil || record.RevokedAt != nil {
+		return ErrRefreshTokenReused
+	}
+	if record.ExpiresAt.Before(now) {
+		return ErrInvalidRefreshToken
+	}
+	return nil
+}
+
+// RefreshTokens 使用刷新令牌换取新的令牌对（刷新令牌轮换）
+// 已轮换或已作废的刷新令牌被再次使用时视为泄露，整个令牌族立即作废
+func RefreshTokens(refreshToken, clientIP string) (*TokenPair, error) {
+	var record model.RefreshToken
+	result := config.DB.Where("token_hash = ?", utils.HashToken(refreshToken)).First(&record)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if err := checkRefreshToken(&record, time.Now()); err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			if revokeErr := RevokeTokenFamily(record.FamilyID, "refresh_reuse"); revokeErr != nil {
+				return nil, revokeErr
+			}
+		}
+		return nil, err
+	}
+
+	var user model.User
+	if err := config.DB.First(&user, record.UserID).Error; err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if user.Status != model.UserStatusActive {
+		if err := RevokeTokenFamily(record.FamilyID, "user_banned"); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("账号已被禁用")
+	}
+
+	// 开始事务
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 条件更新保证并发刷新时只有一个请求能完成轮换
+	updateResult := tx.Model(&model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", record.ID).
+		Update("used_at", time.Now())
+	if updateResult.Error != nil {
+		tx.Rollback()
+		return nil, updateResult.Error
+	}
+	if updateResult.RowsAffected == 0 {
+		tx.Rollback()
+		if err := RevokeTokenFamily(record.FamilyID, "refresh_reuse"); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	// 延长会话有效期（早期签发的令牌族可能没有对应的会话）
+	var session model.Session
+	sessionResult := tx.Where("family_id = ?", record.FamilyID).Limit(1).Find(&session)
+	if sessionResult.Error != nil {
+		tx.Rollback()
+		return nil, sessionResult.Error
+	}
+	if session.ID != 0 {
+		now := time.Now()
+		sessionResult = tx.Model(&session).Updates(map[string]interface{}{
+			"ip":           clientIP,
+			"last_used_at": now,
+			"expires_at":   now.Add(refreshTokenTTL()),
+		})
+		if sessionResult.Error != nil {
+			tx.Rollback()
+			return nil, sessionResult.Error
+		}
+	}
+
+	pair, err := issueTokenPair(tx, &user, record.FamilyID, session.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// RevokeAccessToken 将访问令牌加入作废列表
+func RevokeAccessToken(jti string, userID uint, expiresAt time.Time, reason string) error {
+	revoked := model.RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+	}
+	result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked)
+	return result.Error
+}
+
+// RevokeTokenFamily 作废整个令牌族（包括族内仍在有效期内的访问令牌）
+func RevokeTokenFamily(familyID, reason string) error {
+	var tokens []model.RefreshToken
+	result := config.DB.Where("family_id = ? AND (revoked_at IS NULL OR created_at > ?)",
+		familyID, time.Now().Add(-accessTokenTTL())).Find(&tokens)
+	if result.Error != nil {
+		return result.Error
+	}
+	if err := revokeRefreshTokens(tokens, reason); err != nil {
+		return err
+	}
+
+	// 同时注销对应的会话
+	result = config.DB.Model(&model.Session{}).Where("family_id = ? AND revoked_at IS NULL", familyID).Update("revoked_at", time.Now())
+	return result.Error
+}
+
+// RevokeAllUserTokens 作废用户的全部令牌，用于修改密码、禁用账号等场景
+func RevokeAllUserTokens(userID uint, reason string) error {
+	var tokens []model.RefreshToken
+	result := config.DB.Where("user_id = ? AND (revoked_at IS NULL OR created_at > ?)",
+		userID, time.Now().Add(-accessTokenTTL())).Find(&tokens)
+	if result.Error != nil {
+		return result.Error
+	}
+	if err := revokeRefreshTokens(tokens, reason); err != nil {
+		return err
+	}
+
+	// 同时注销用户的全部会话
+	result = config.DB.Model(&model.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", time.Now())
+	return result.Error
+}
+
+// revokeRefreshTokens 作废刷新令牌，并将与之一同签发且未过期的访问令牌加入作废列表
+func revokeRefreshTokens(tokens []model.RefreshToken, reason string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	ids := make([]uint, 0, len(tokens))
+	var revoked []model.RevokedToken
+	for _, token := range tokens {
+		ids = append(ids, token.ID)
+		accessExpiresAt := token.CreatedAt.Add(accessTokenTTL())
+		if token.AccessJTI != "" && accessExpiresAt.After(now) {
+			revoked = append(revoked, model.RevokedToken{
+				JTI:       token.AccessJTI,
+				UserID:    token.UserID,
+				Reason:    reason,
+				ExpiresAt: accessExpiresAt,
+			})
+		}
+	}
+
+	// 开始事务
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result := tx.Model(&model.RefreshToken{}).Where("id IN ? AND revoked_at IS NULL", ids).Update("revoked_at", now)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+
+	if len(revoked) > 0 {
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked)
+		if result.Error != nil {
+			tx.Rollback()
+			return result.Error
+		}
+	}
+
+	// 提交事务
+	return tx.Commit().Error
+}
+
+// Logout 登出：作废当前访问令牌及其所属的令牌族
+func Logout(jti string, userID uint, expiresAt time.Time) error {
+	if err := RevokeAccessToken(jti, userID, expiresAt, "logout"); err != nil {
+		return err
+	}
+
+	var record model.RefreshToken
+	result := config.DB.Where("access_jti = ?", jti).First(&record)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return RevokeTokenFamily(record.FamilyID, "logout")
+}
+
+// IsTokenRevoked 检查访问令牌是否已被作废
+func IsTokenRevoked(jti string) (bool, error) {
+	var count int64
+	result := config.DB.Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+// PurgeExpiredTokens 清理已过期的刷新令牌、作废记录、会话、一次性令牌和第三方登录状态
+func PurgeExpiredTokens() error {
+	now := time.Now()
+	if err := config.DB.Where("expires_at < ?", now).Delete(&model.RevokedToken{}).Error; err != nil {
+		return err
+	}
+	if err := config.DB.Where("expires_at < ?", now).Delete(&model.RefreshToken{}).Error; err != nil {
+		return err
+	}
+	if err := config.DB.Where("expires_at < ?", now).Delete(&model.Session{}).Error; err != nil {
+		return err
+	}
+	if err := config.DB.Where("expires_at < ?", now).Delete(&model.UserToken{}).Error; err != nil {
+		return err
+	}
+	return config.DB.Where("expires_at < ?", now).Delete(&model.OAuthState{}).Error
+}
+
+// StartTokenCleanup 定期清理过期令牌记录和登录失败计数，应在独立的 goroutine 中运行
+func StartTokenCleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := PurgeExpiredTokens(); err != nil {
+			fmt.Printf("清理过期令牌失败: %v\n", err)
+		}
+		if err := PurgeStaleLoginFailures(); err != nil {
+			fmt.Printf("清理登录失败计数失败: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"gin-blog-system/model"
+	"testing"
+	"time"
+)
+
+func TestCheckRefreshToken(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Minute)
+	tests := []struct {
+		name    string
+		record  model.RefreshToken
+		wantErr error
+	}{
+		{
+			name:   "未使用且未过期",
+			record: model.RefreshToken{ExpiresAt: now.Add(time.Hour)},
+		},
+		{
+			name:    "已轮换的令牌再次使用",
+			record:  model.RefreshToken{ExpiresAt: now.Add(time.Hour), UsedAt: &earlier},
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name:    "已作废的令牌再次使用",
+			record:  model.RefreshToken{ExpiresAt: now.Add(time.Hour), RevokedAt: &earlier},
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name:    "已过期",
+			record:  model.RefreshToken{ExpiresAt: earlier},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			// 过期后被重放的已轮换令牌同样视为泄露
+			name:    "已过期且已轮换",
+			record:  model.RefreshToken{ExpiresAt: earlier, UsedAt: &earlier},
+			wantErr: ErrRefreshTokenReused,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRefreshToken(&tt.record, now)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("checkRefreshToken() error = %v, want nil", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkRefreshToken() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 两步验证相关参数
+const (
+	twoFactorChallengeTTL = 5 * time.Minute
+	twoFactorMaxAttempts  = 5
+	recoveryCodeCount     = 10
+	totpIssuer            = "Gin Blog System"
+)
+
+// ErrInvalidTwoFactorCode 两步验证码错误
+var ErrInvalidTwoFactorCode = errors.New("验证码错误")
+
+// TwoFactorSetup 两步验证绑定信息
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// CheckEncryptionKey 检查敏感字段加密密钥已配置：未配置 encryption_key 时由 jwt_secret 派生，
+// 两者都为空（使用非对称JWT密钥且未配置 encryption_key）时拒绝启动，服务启动时调用
+func CheckEncryptionKey() error {
+	if config.AppConfig.App.EncryptionKey == "" && config.AppConfig.App.JWTSecret == "" {
+		return errors.New("未配置 jwt_secret 时必须配置 encryption_key")
+	}
+	return nil
+}
+
+// encryptionKey 获取敏感字段加密密钥（32字节），同时用于派生分页游标签名密钥
+func encryptionKey() []byte {
+	key := config.AppConfig.App.EncryptionKey
+	if key == "" {
+		key = config.AppConfig.App.JWTSecret
+	}
+	sum := sha256.Sum256([]byte(key))
+	return sum[:]
+}
+
+// SetupTwoFactor 生成新的TOTP密钥，需调用 ConfirmTwoFactor 校验验证码后才会启用
+func SetupTwoFactor(userID uint) (*TwoFactorSetup, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New("两步验证已启用")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := utils.EncryptString(secret, encryptionKey())
+	if err != nil {
+		return nil, err
+	}
+
+	result := config.DB.Model(user).Updates(map[string]interface{}{
+		"totp_secret":    encrypted,
+		"totp_last_step": 0,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &TwoFactorSetup{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPAuthURI(totpIssuer, user.Username, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor 校验验证码并启用两步验证，返回一次性恢复码（仅展示一次）
+func ConfirmTwoFactor(userID uint, code string) ([]string, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New("两步验证已启用")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("请先获取两步验证密钥")
+	}
+
+	step, ok := validateUserTOTP(user, code)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	result := config.DB.Model(user).Updates(map[string]interface{}{
+		"two_factor_enabled": true,
+		"totp_last_step":     step,
+		"recovery_codes":     hashes,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return codes, nil
+}
+
+// DisableTwoFactor 关闭两步验证，需要提供当前密码和验证码（或恢复码）
+func DisableTwoFactor(userID uint, password, code string) error {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled {
+		return errors.New("两步验证未启用")
+	}
+	if !CheckPasswordHash(password, user.Password) {
+		return errors.New("密码错误")
+	}
+	if err := verifyTwoFactorCode(user, code); err != nil {
+		return err
+	}
+
+	result := config.DB.Model(user).Updates(map[string]interface{}{
+		"two_factor_enabled": false,
+		"totp_secret":        "",
+		"totp_last_step":     0,
+		"recovery_codes":     "",
+	})
+	return result.Error
+}
+
+// CreateTwoFactorChallenge 密码校验通过后为启用两步验证的用户创建登录挑战令牌
+func CreateTwoFactorChallenge(userID uint) (string, int64, error) {
+	token, err := createUserToken(userID, model.TokenPurposeTwoFactor, twoFactorChallengeTTL)
+	if err != nil {
+		return "", 0, err
+	}
+	return token, int64(twoFactorChallengeTTL.Seconds()), nil
+}
+
+// VerifyTwoFactorChallenge 使用验证码（或恢复码）兑换挑战令牌，成功后返回用户。
+// 错误的验证码与密码错误一样按账号和IP计入登录失败次数，兑换成功后才清除账号的失败计数
+func VerifyTwoFactorChallenge(challengeToken, code, userAgent string, actx AuditContext) (*model.User, error) {
+	var record model.UserToken
+	result := config.DB.Where("token_hash = ? AND purpose = ?", utils.HashToken(challengeToken), model.TokenPurposeTwoFactor).First(&record)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidUserToken
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if record.UsedAt != nil || record.ExpiresAt.Before(time.Now()) || record.Attempts >= twoFactorMaxAttempts {
+		return nil, ErrInvalidUserToken
+	}
+
+	user, err := GetUserByID(record.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	accountKey := accountFailureKey(user.ID, "")
+	ipKey := ipFailureKey(actx.IP)
+	if err := checkLoginLocked(ipKey, accountKey); err != nil {
+		recordLoginAttempt(user.ID, user.Username, userAgent, actx, false, "two_factor_locked")
+		return nil, err
+	}
+
+	if err := verifyTwoFactorCode(user, code); err != nil {
+		// 记录失败次数，达到上限后挑战令牌失效
+		updates := map[string]interface{}{"attempts": gorm.Expr("attempts + ?", 1)}
+		if record.Attempts+1 >= twoFactorMaxAttempts {
+			updates["used_at"] = time.Now()
+		}
+		if updateErr := config.DB.Model(&record).Updates(updates).Error; updateErr != nil {
+			return nil, updateErr
+		}
+		if !errors.Is(err, ErrInvalidTwoFactorCode) {
+			return nil, err
+		}
+		recordLoginAttempt(user.ID, user.Username, userAgent, actx, false, "invalid_two_factor")
+		if lockErr := registerLoginFailure(accountKey, ipKey); lockErr != nil {
+			return nil, lockErr
+		}
+		return nil, err
+	}
+
+	// 条件更新保证挑战令牌只能兑换一次
+	updateResult := config.DB.Model(&model.UserToken{}).
+		Where("id = ? AND used_at IS NULL", record.ID).
+		Update("used_at", time.Now())
+	if updateResult.Error != nil {
+		return nil, updateResult.Error
+	}
+	if updateResult.RowsAffected == 0 {
+		return nil, ErrInvalidUserToken
+	}
+
+	if user.Status != model.UserStatusActive {
+		recordLoginAttempt(user.ID, user.Username, userAgent, actx, false, "disabled")
+		return nil, errors.New("账号已被禁用")
+	}
+
+	if err := loginAttemptStore.Reset(accountKey); err != nil {
+		return nil, err
+	}
+	recordLoginAttempt(user.ID, user.Username, userAgent, actx, true, "")
+
+	// 不返回密码字段
+	user.Password = ""
+	return user, nil
+}
+
+// verifyTwoFactorCode 校验TOTP验证码或恢复码，校验成功后记录时间步或消耗恢复码
+func verifyTwoFactorCode(user *model.User, code string) error {
+	if step, ok := validateUserTOTP(user, code); ok {
+		// 条件更新防止同一验证码被重复使用
+		result := config.DB.Model(&model.User{}).
+			Where("id = ? AND totp_last_step < ?", user.ID, step).
+			Update("totp_last_step", step)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	return consumeRecoveryCode(user, code)
+}
+
+// validateUserTOTP 使用用户的TOTP密钥校验验证码
+func validateUserTOTP(user *model.User, code string) (int64, bool) {
+	if user.TOTPSecret == "" {
+		return 0, false
+	}
+	secret, err := utils.DecryptString(user.TOTPSecret, encryptionKey())
+	if err != nil {
+		return 0, false
+	}
+
+	step, ok := utils.ValidateTOTP(secret, code, time.Now())
+	if !ok || step <= user.TOTPLastStep {
+		return 0, false
+	}
+	return step, true
+}
+
+// consumeRecoveryCode 校验并消耗一个恢复码
+func consumeRecoveryCode(user *model.User, code string) error {
+	if user.RecoveryCodes == "" {
+		return ErrInvalidTwoFactorCode
+	}
+
+	var hashes []string
+	if err := json.Unmarshal([]byte(user.RecoveryCodes), &hashes); err != nil {
+		return ErrInvalidTwoFactorCode
+	}
+
+	codeHash := utils.HashToken(normalizeRecoveryCode(code))
+	for i, hash := range hashes {
+		if hash != codeHash {
+			continue
+		}
+
+		remaining := append(hashes[:i:i], hashes[i+1:]...)
+		data, err := json.Marshal(remaining)
+		if err != nil {
+			return err
+		}
+
+		// 条件更新防止并发请求重复使用同一个恢复码
+		result := config.DB.Model(&model.User{}).
+			Where("id = ? AND recovery_codes = ?", user.ID, user.RecoveryCodes).
+			Update("recovery_codes", string(data))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+	return ErrInvalidTwoFactorCode
+}
+
+// generateRecoveryCodes 生成恢复码，返回明文列表和哈希列表（JSON）
+func generateRecoveryCodes() ([]string, string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, "", err
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		code := raw[:4] + "-" + raw[4:]
+		codes = append(codes, code)
+		hashes = append(hashes, utils.HashToken(normalizeRecoveryCode(code)))
+	}
+
+	data, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, "", err
+	}
+	return codes, string(data), nil
+}
+
+// normalizeRecoveryCode 规范化恢复码（忽略大小写、空格和连字符）
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"bytes"
+	"gin-blog-system/config"
+	"testing"
+)
+
+func TestCheckEncryptionKey(t *testing.T) {
+	defer func(conf *config.AppConf) { config.AppConfig = conf }(config.AppConfig)
+
+	tests := []struct {
+		name          string
+		jwtSecret     string
+		encryptionKey string
+		wantErr       bool
+	}{
+		{name: "只配置 encryption_key", encryptionKey: "key"},
+		{name: "由 jwt_secret 派生", jwtSecret: "secret"},
+		{name: "两者都配置", jwtSecret: "secret", encryptionKey: "key"},
+		{name: "两者都为空", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig = &config.AppConf{}
+			config.AppConfig.App.JWTSecret = tt.jwtSecret
+			config.AppConfig.App.EncryptionKey = tt.encryptionKey
+			if err := CheckEncryptionKey(); (err != nil) != tt.wantErr {
+				t.Fatalf("CheckEncryptionKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncryptionKeyPrefersEncryptionKey(t *testing.T) {
+	defer func(conf *config.AppConf) { config.AppConfig = conf }(config.AppConfig)
+	config.AppConfig = &config.AppConf{}
+	config.AppConfig.App.JWTSecret = "secret"
+
+	derived := encryptionKey()
+	config.AppConfig.App.EncryptionKey = "key"
+	if len(derived) != 32 || bytes.Equal(derived, encryptionKey()) {
+		t.Fatal("配置 encryption_key 后应使用它而不是 jwt_secret")
+	}
+}
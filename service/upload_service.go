@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"gin-blog-system/config"
+	"gin-blog-system/model"
 	"gin-blog-system/utils"
 	"math/rand"
 	"mime/multipart"
@@ -10,8 +11,8 @@ import (
 	"time"
 )
 
-// UploadImage 上传图片
-func UploadImage(fileHeader *multipart.FileHeader) (string, error) {
+// UploadImage 上传图片，并记录文件所属用户
+func UploadImage(fileHeader *multipart.FileHeader, userID uint) (string, error) {
 	// 检查文件类型
 	fileType := fileHeader.Header.Get("Content-Type")
 	allowedTypes := config.AppConfig.Upload.AllowedTypes
@@ -37,8 +38,20 @@ func UploadImage(fileHeader *multipart.FileHeader) (string, error) {
 		return "", fmt.Errorf("保存文件失败: %v", err)
 	}
 
+	filePath := filepath.Join(relativePath, filename)
+	record := model.Upload{
+		UserID:       userID,
+		Path:         filePath,
+		OriginalName: fileHeader.Filename,
+		ContentType:  fileType,
+		Size:         fileHeader.Size,
+	}
+	if err := config.DB.Create(&record).Error; err != nil {
+		return "", fmt.Errorf("保存上传记录失败: %v", err)
+	}
+
 	// 返回相对URL路径（不包含uploads前缀，因为static已映射到uploads目录）
-	return filepath.Join(relativePath, filename), nil
+	return filePath, nil
 }
 
 // generateRandomString 生成随机字符串
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"strconv"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// ProfileUpdate 个人资料更新内容，nil 字段表示不修改
+type ProfileUpdate struct {
+	Nickname *string
+	Avatar   *string
+	Bio      *string
+}
+
+// GetUserByRef 根据用户ID或用户名获取正常状态的用户，ref 为纯数字时按ID查找，否则按用户名查找
+func GetUserByRef(ref string) (*model.User, error) {
+	query := config.DB.Where("status = ?", model.UserStatusActive)
+	if id, err := strconv.ParseUint(ref, 10, 32); err == nil {
+		query = query.Where("id = ?", id)
+	} else {
+		query = query.Where("username = ?", ref)
+	}
+
+	var user model.User
+	result := query.First(&user)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, errors.New("用户不存在")
+	}
+	return &user, result.Error
+}
+
+// Validate 校验资料字段的长度
+func (update ProfileUpdate) Validate() error {
+	if update.Nickname != nil && utf8.RuneCountInString(*update.Nickname) > 50 {
+		return errors.New("昵称不能超过50个字符")
+	}
+	if update.Bio != nil && utf8.RuneCountInString(*update.Bio) > 500 {
+		return errors.New("个人简介不能超过500个字符")
+	}
+	return nil
+}
+
+// UpdateUserProfile 更新用户的昵称、头像和个人简介
+func UpdateUserProfile(userID uint, update ProfileUpdate) (*model.User, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if update.Nickname != nil {
+		updates["nickname"] = *update.Nickname
+	}
+	if update.Avatar != nil {
+		updates["avatar"] = *update.Avatar
+	}
+	if update.Bio != nil {
+		updates["bio"] = *update.Bio
+	}
+
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(updates) == 0 {
+		return user, nil
+	}
+
+	if err := config.DB.Model(user).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return GetUserByID(userID)
+}
+
+// ChangePassword 校验当前密码后修改密码，并作废用户的全部令牌
+func ChangePassword(userID uint, currentPassword, newPassword string) error {
+	if len(newPassword) < 6 {
+		return errors.New("密码长度不能少于6位")
+	}
+
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if !CheckPasswordHash(currentPassword, user.Password) {
+		return errors.New("当前密码错误")
+	}
+
+	hashedPassword, err := HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	result := config.DB.Model(&model.User{}).Where("id = ?", userID).Update("password", hashedPassword)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return RevokeAllUserTokens(userID, "password_changed")
+}
+
+// GetUserStats 统计用户已发布文章的数量、浏览量、点赞数和评论数
+func GetUserStats(userID uint) (*model.UserStatsResponse, error) {
+	var stats model.UserStatsResponse
+	result := config.DB.Model(&model.Article{}).
+		Select("COUNT(*) AS article_count, COALESCE(SUM(view_count), 0) AS view_count, "+
+			"COALESCE(SUM(like_count), 0) AS like_count, COALESCE(SUM(comment_count), 0) AS comment_count").
+		Where("user_id = ? AND status = ?", userID, model.ArticleStatusPublished).
+		Scan(&stats)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &stats, nil
+}
+
+// GetUserProfile 获取用户公开主页
+func GetUserProfile(ref string) (*model.UserProfileResponse, error) {
+	user, err := GetUserByRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := GetUserStats(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	followers, following, err := GetFollowCounts(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UserProfileResponse{
+		User:           *user.ConvertToPublicUserResponse(),
+		Stats:          *stats,
+		FollowerCount:  followers,
+		FollowingCount: following,
+	}, nil
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"gin-blog-system/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 一次性令牌有效期
+const (
+	passwordResetTokenTTL = 30 * time.Minute
+	emailVerifyTokenTTL   = 24 * time.Hour
+)
+
+// ErrInvalidUserToken 一次性令牌无效或已过期
+var ErrInvalidUserToken = errors.New("链接无效或已过期")
+
+// createUserToken 为用户创建一次性令牌，同一用途的旧令牌同时失效
+func createUserToken(userID uint, purpose string, ttl time.Duration) (string, error) {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	// 开始事务
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result := tx.Model(&model.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		tx.Rollback()
+		return "", result.Error
+	}
+
+	record := model.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// consumeUserToken 校验并消费一次性令牌，返回令牌所属用户ID
+func consumeUserToken(tx *gorm.DB, token, purpose string) (uint, error) {
+	var record model.UserToken
+	result := tx.Where("token_hash = ? AND purpose = ?", utils.HashToken(token), purpose).First(&record)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return 0, ErrInvalidUserToken
+	}
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if record.UsedAt != nil || record.ExpiresAt.Before(time.Now()) {
+		return 0, ErrInvalidUserToken
+	}
+
+	// 条件更新保证令牌只能被使用一次
+	updateResult := tx.Model(&model.UserToken{}).
+		Where("id = ? AND used_at IS NULL", record.ID).
+		Update("used_at", time.Now())
+	if updateResult.Error != nil {
+		return 0, updateResult.Error
+	}
+	if updateResult.RowsAffected == 0 {
+		return 0, ErrInvalidUserToken
+	}
+	return record.UserID, nil
+}
+
+// RequestPasswordReset 申请重置密码，向用户邮箱发送重置链接
+// 邮箱不存在时同样返回成功，避免泄露账号是否存在
+func RequestPasswordReset(email string) error {
+	var user model.User
+	result := config.DB.Where("email = ?", email).First(&user)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if user.Status != model.UserStatusActive {
+		return nil
+	}
+
+	token, err := createUserToken(user.ID, model.TokenPurposePasswordReset, passwordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	// 异步发送，使接口响应时间与邮箱是否存在无关
+	go func() {
+		if err := sendPasswordResetMail(user.Email, user.Username, token); err != nil {
+			fmt.Printf("发送重置密码邮件失败: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// ResetPassword 使用重置令牌设置新密码，并作废该用户的全部登录令牌
+func ResetPassword(token, newPassword string) error {
+	if len(newPassword) < 6 {
+		return errors.New("密码长度不能少于6位")
+	}
+
+	hashedPassword, err := HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	// 开始事务
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	userID, err := consumeUserToken(tx, token, model.TokenPurposePasswordReset)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	result := tx.Model(&model.User{}).Where("id = ?", userID).Update("password", hashedPassword)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return RevokeAllUserTokens(userID, "password_reset")
+}
+
+// SendEmailVerification 向用户发送邮箱验证邮件
+func SendEmailVerification(userID uint) error {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return errors.New("邮箱已验证")
+	}
+	if user.Email == "" {
+		return errors.New("未设置邮箱")
+	}
+
+	token, err := createUserToken(user.ID, model.TokenPurposeEmailVerify, emailVerifyTokenTTL)
+	if err != nil {
+		return err
+	}
+	return sendVerificationMail(user.Email, user.Username, token)
+}
+
+// VerifyEmail 使用验证令牌确认邮箱
+func VerifyEmail(token string) error {
+	// 开始事务
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	userID, err := consumeUserToken(tx, token, model.TokenPurposeEmailVerify)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	result := tx.Model(&model.User{}).Where("id = ?", userID).Update("email_verified", true)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+
+	// 提交事务
+	return tx.Commit().Error
+}
@@ -0,0 +1,435 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gin-blog-system/config"
+	"gin-blog-system/model"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 编辑流程中的操作
+const (
+	ArticleActionSubmit         = "submit"          // 作者提交审核
+	ArticleActionWithdraw       = "withdraw"        // 作者撤回为草稿
+	ArticleActionApprove        = "approve"         // 审核通过
+	ArticleActionRequestChanges = "request_changes" // 审核要求修改
+	ArticleActionPublish        = "publish"         // 发布（指定未来的发布时间时为定时发布）
+	ArticleActionArchive        = "archive"         // 归档（下线）
+	ArticleActionReopen         = "reopen"          // 将归档的文章重新打开为草稿
+)
+
+// 执行操作所需的身份
+const (
+	workflowOwner     = iota // 文章作者或可编辑任何文章的角色
+	workflowReviewer         // 拥有审核权限的角色，不能审核自己的文章，指定了审核人时只能由审核人审核
+	workflowPublisher        // 拥有发布权限的角色
+)
+
+// articleTransition 状态流转规则
+type articleTransition struct {
+	from []int // 允许执行操作的状态
+	to   int   // 操作后的状态
+	who  int   // 执行操作所需的身份
+}
+
+// articleTransitions 编辑流程的状态机
+var articleTransitions = map[string]articleTransition{
+	ArticleActionSubmit: {
+		from: []int{model.ArticleStatusDraft, model.ArticleStatusChangesRequested},
+		to:   model.ArticleStatusSubmitted,
+		who:  workflowOwner,
+	},
+	ArticleActionWithdraw: {
+		from: []int{model.ArticleStatusSubmitted, model.ArticleStatusApproved},
+		to:   model.ArticleStatusDraft,
+		who:  workflowOwner,
+	},
+	ArticleActionApprove: {
+		from: []int{model.ArticleStatusSubmitted},
+		to:   model.ArticleStatusApproved,
+		who:  workflowReviewer,
+	},
+	ArticleActionRequestChanges: {
+		from: []int{model.ArticleStatusSubmitted, model.ArticleStatusApproved},
+		to:   model.ArticleStatusChangesRequested,
+		who:  workflowReviewer,
+	},
+	ArticleActionPublish: {
+		from: []int{model.ArticleStatusApproved},
+		to:   model.ArticleStatusPublished,
+		who:  workflowPublisher,
+	},
+	ArticleActionArchive: {
+		from: []int{model.ArticleStatusPublished, model.ArticleStatusScheduled},
+		to:   model.ArticleStatusArchived,
+		who:  workflowOwner,
+	},
+	ArticleActionReopen: {
+		from: []int{model.ArticleStatusArchived},
+		to:   model.ArticleStatusDraft,
+		who:  workflowOwner,
+	},
+}
+
+// articleActionOrder 操作的展示顺序
+var articleActionOrder = []string{
+	ArticleActionSubmit,
+	ArticleActionWithdraw,
+	ArticleActionApprove,
+	ArticleActionRequestChanges,
+	ArticleActionPublish,
+	ArticleActionArchive,
+	ArticleActionReopen,
+}
+
+// ErrInvalidTransition 当前状态不允许执行该操作
+var ErrInvalidTransition = errors.New("无效的状态流转")
+
+// ErrUnknownArticleAction 未知的流程操作
+var ErrUnknownArticleAction = errors.New("未知的操作")
+
+// TransitionInput 状态流转的附加参数
+type TransitionInput struct {
+	Comment     string     // 审核意见，要求修改时必填
+	PublishAt   *time.Time // 发布时间，晚于当前时间时定时发布（仅 publish）
+	UnpublishAt *time.Time // 定时下线时间（仅 publish）
+}
+
+// ArticleWorkflow 文章当前的流程状态以及当前用户可以执行的操作
+type ArticleWorkflow struct {
+	ArticleID  uint     `json:"article_id"`
+	Status     int      `json:"status"`
+	State      string   `json:"state"`
+	ReviewerID uint     `json:"reviewer_id"`
+	Actions    []string `json:"actions"`
+}
+
+// checkWorkflowRole 检查用户是否具备执行操作所需的身份
+func checkWorkflowRole(article *model.Article, who int, userID uint, role string) error {
+	switch who {
+	case workflowOwner:
+		// 署名的作者都可以提交、撤回
+		return CheckArticlePermission(article.ID, userID, role)
+	case workflowReviewer:
+		if !model.HasPermission(role, model.PermArticleReview) {
+			break
+		}
+		// 管理员不受审核人限制
+		if role == model.RoleAdmin {
+			return nil
+		}
+		credited, err := isArticleAuthor(article.ID, userID)
+		if err != nil {
+			return err
+		}
+		if article.UserID == userID || credited {
+			return fmt.Errorf("%w：不能审核自己的文章", ErrPermissionDenied)
+		}
+		if article.ReviewerID != 0 && article.ReviewerID != userID {
+			return fmt.Errorf("%w：该文章已指定其他审核人", ErrPermissionDenied)
+		}
+		return nil
+	case workflowPublisher:
+		if model.HasPermission(role, model.PermArticlePublish) {
+			return nil
+		}
+	}
+	return ErrPermissionDenied
+}
+
+// statusIn 检查状态是否在列表中
+func statusIn(status int, list []int) bool {
+	for _, s := range list {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidTransition 生成状态流转错误，说明当前状态和允许执行该操作的状态
+func invalidTransition(action string, status int, from []int) error {
+	names := make([]string, len(from))
+	for i, s := range from {
+		names[i] = model.ArticleStatusName(s)
+	}
+	return fmt.Errorf("%w：文章当前状态为 %s，不能执行 %s（仅允许在 %s 状态下执行）",
+		ErrInvalidTransition, model.ArticleStatusName(status), action, strings.Join(names, "、"))
+}
+
+// checkArticleEditable 检查文章当前状态是否允许修改内容，已提交审核或审核通过的文章需要先撤回，
+// 定时发布的文章需要先归档再重新打开；已发布的文章修改后不再经过审核，只有可编辑任何文章的角色可以直接修改
+func checkArticleEditable(article *model.Article, role string) error {
+	switch article.Status {
+	case model.ArticleStatusSubmitted, model.ArticleStatusApproved:
+		return fmt.Errorf("%w：文章当前状态为 %s，不能修改内容（请先执行 %s）",
+			ErrInvalidTransition, model.ArticleStatusName(article.Status), ArticleActionWithdraw)
+	case model.ArticleStatusScheduled:
+		return fmt.Errorf("%w：文章当前状态为 %s，不能修改内容（请先执行 %s 和 %s 后重新提交审核）",
+			ErrInvalidTransition, model.ArticleStatusName(article.Status), ArticleActionArchive, ArticleActionReopen)
+	case model.ArticleStatusPublished:
+		if !model.HasPermission(role, model.PermArticleEditAny) {
+			return fmt.Errorf("%w：文章已发布，只有编辑可以直接修改内容（请先执行 %s 和 %s 后重新提交审核）",
+				ErrInvalidTransition, ArticleActionArchive, ArticleActionReopen)
+		}
+	}
+	return nil
+}
+
+// TransitionArticle 执行编辑流程操作，检查操作者身份和当前状态，返回更新后的文章
+// 身份不符时返回 ErrPermissionDenied，当前状态不允许时返回 ErrInvalidTransition
+func TransitionArticle(articleID uint, action, role string, input TransitionInput, actx AuditContext) (*model.Article, error) {
+	transition, ok := articleTransitions[action]
+	if !ok {
+		return nil, ErrUnknownArticleAction
+	}
+
+	var article model.Article
+	result := config.DB.First(&article, articleID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, errors.New("文章不存在")
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if err := checkWorkflowRole(&article, transition.who, actx.ActorID, role); err != nil {
+		return nil, err
+	}
+	if !statusIn(article.Status, transition.from) {
+		return nil, invalidTransition(action, article.Status, transition.from)
+	}
+
+	comment := strings.TrimSpace(input.Comment)
+	if action == ArticleActionRequestChanges && comment == "" {
+		return nil, errors.New("要求修改时请填写审核意见")
+	}
+
+	updates := map[string]interface{}{"status": transition.to}
+	if action == ArticleActionPublish {
+		now := time.Now()
+		publishAt := &now
+		if input.PublishAt != nil && input.PublishAt.After(now) {
+			publishAt = input.PublishAt
+			updates["status"] = model.ArticleStatusScheduled
+		}
+		if err := validateUnpublishAt(input.UnpublishAt, publishAt, now); err != nil {
+			return nil, err
+		}
+		updates["publish_at"] = publishAt
+		updates["unpublish_at"] = input.UnpublishAt
+	}
+	if action == ArticleActionArchive {
+		updates["unpublish_at"] = nil
+	}
+
+	// 开始事务
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 条件更新，避免并发操作时基于过期的状态流转
+	result = tx.Model(&model.Article{}).Where("id = ? AND status = ?", articleID, article.Status).Updates(updates)
+	if result.Error != nil {
+		tx.Rollback()
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, fmt.Errorf("%w：文章状态已被其他人修改，请刷新后重试", ErrInvalidTransition)
+	}
+
+	if comment != "" {
+		review := model.ReviewComment{
+			ArticleID: articleID,
+			UserID:    actx.ActorID,
+			Action:    action,
+			Content:   comment,
+		}
+		if err := tx.Create(&review).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	before := article
+	if err := tx.First(&article, articleID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := recordAudit(tx, actx, "article."+action, AuditTargetArticle, articleID, before, article); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	refreshSearchIndex(articleID)
+	return &article, nil
+}
+
+// GetArticleWorkflow 获取文章的流程状态以及当前用户可以执行的操作
+func GetArticleWorkflow(articleID, userID uint, role string) (*ArticleWorkflow, error) {
+	var article model.Article
+	result := config.DB.First(&article, articleID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, errors.New("文章不存在")
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	workflow := &ArticleWorkflow{
+		ArticleID:  article.ID,
+		Status:     article.Status,
+		State:      model.ArticleStatusName(article.Status),
+		ReviewerID: article.ReviewerID,
+		Actions:    []string{},
+	}
+	for _, action := range articleActionOrder {
+		transition := articleTransitions[action]
+		if statusIn(article.Status, transition.from) && checkWorkflowRole(&article, transition.who, userID, role) == nil {
+			workflow.Actions = append(workflow.Actions, action)
+		}
+	}
+	return workflow, nil
+}
+
+// AssignReviewer 为文章指定审核人，reviewerID 为 0 时取消指定
+// 只有拥有审核权限的角色可以指定，审核人必须拥有审核权限且不能是文章作者
+func AssignReviewer(articleID, reviewerID uint, role string, actx AuditContext) error {
+	if !model.HasPermission(role, model.PermArticleReview) {
+		return ErrPermissionDenied
+	}
+
+	var article model.Article
+	result := config.DB.First(&article, articleID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return errors.New("文章不存在")
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if article.Status == model.ArticleStatusPublished || article.Status == model.ArticleStatusArchived {
+		return fmt.Errorf("%w：文章当前状态为 %s，不需要审核", ErrInvalidTransition, model.ArticleStatusName(article.Status))
+	}
+
+	if reviewerID != 0 {
+		var reviewer model.User
+		if err := config.DB.Select("id", "role", "status").First(&reviewer, reviewerID).Error; err != nil {
+			return errors.New("审核人不存在")
+		}
+		if reviewer.Status != model.UserStatusActive || !model.HasPermission(reviewer.Role, model.PermArticleReview) {
+			return errors.New("该用户没有审核权限")
+		}
+		credited, err := isArticleAuthor(article.ID, reviewer.ID)
+		if err != nil {
+			return err
+		}
+		// 合著者、编辑、译者等署名作者同样不能审核这篇文章
+		if reviewer.ID == article.UserID || credited {
+			return errors.New("审核人不能是文章作者")
+		}
+	}
+
+	result = config.DB.Model(&article).Update("reviewer_id", reviewerID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	logAudit(actx, AuditArticleAssignReviewer, AuditTargetArticle, articleID,
+		map[string]uint{"reviewer_id": article.ReviewerID}, map[string]uint{"reviewer_id": reviewerID})
+	return nil
+}
+
+// CheckReviewAccess 检查用户是否可以查看和发表审核意见：署名的作者、指定的审核人或拥有审核权限的角色
+func CheckReviewAccess(articleID, userID uint, role string) error {
+	var article model.Article
+	result := config.DB.Select("id", "user_id", "reviewer_id").First(&article, articleID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return errors.New("文章不存在")
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if article.UserID == userID || article.ReviewerID == userID || model.HasPermission(role, model.PermArticleReview) {
+		return nil
+	}
+	credited, err := isArticleAuthor(articleID, userID)
+	if err != nil {
+		return err
+	}
+	if credited {
+		return nil
+	}
+	return ErrPermissionDenied
+}
+
+// CreateReviewComment 发表审核意见（不改变文章状态）
+func CreateReviewComment(articleID, userID uint, content string) (*model.ReviewComment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.New("审核意见不能为空")
+	}
+
+	review := model.ReviewComment{
+		ArticleID: articleID,
+		UserID:    userID,
+		Content:   content,
+	}
+	if err := config.DB.Create(&review).Error; err != nil {
+		return nil, err
+	}
+	if err := config.DB.Preload("User").First(&review, review.ID).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetReviewComments 获取文章的审核意见（按时间正序）
+func GetReviewComments(articleID uint) ([]model.ReviewCommentResponse, error) {
+	var reviews []model.ReviewComment
+	result := config.DB.Where("article_id = ?", articleID).Preload("User").Order("id ASC").Find(&reviews)
+
+	responses := make([]model.ReviewCommentResponse, len(reviews))
+	for i, review := range reviews {
+		responses[i] = *review.ConvertToReviewCommentResponse()
+	}
+	return responses, result.Error
+}
+
+// GetReviewQueue 获取等待审核的文章：未指定审核人或指定给当前用户的、当前用户未署名的文章（管理员可以看到全部）
+func GetReviewQueue(userID uint, role string, page, pageSize int) ([]model.ArticleResponse, int64, error) {
+	if !model.HasPermission(role, model.PermArticleReview) {
+		return nil, 0, ErrPermissionDenied
+	}
+
+	var articles []model.Article
+	var total int64
+
+	db := config.DB.Model(&model.Article{}).Where("status = ?", model.ArticleStatusSubmitted).
+		Preload("User").Preload("Category").Preload("Tags").Scopes(preloadArticleAuthors)
+	if role != model.RoleAdmin {
+		db = db.Where("user_id <> ? AND reviewer_id IN ?", userID, []uint{0, userID}).
+			Where("id NOT IN (?)", articleIDsCreditedTo(userID))
+	}
+
+	// 计算总数
+	db.Count(&total)
+
+	// 分页查询（先提交的先审核）
+	offset := (page - 1) * pageSize
+	result := db.Order("updated_at ASC").Offset(offset).Limit(pageSize).Find(&articles)
+
+	return convertArticles(articles), total, result.Error
+}
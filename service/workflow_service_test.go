@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"gin-blog-system/model"
+	"testing"
+)
+
+func TestArticleTransitions(t *testing.T) {
+	tests := []struct {
+		action  string
+		from    int
+		allowed bool
+		to      int
+	}{
+		{ArticleActionSubmit, model.ArticleStatusDraft, true, model.ArticleStatusSubmitted},
+		{ArticleActionSubmit, model.ArticleStatusChangesRequested, true, model.ArticleStatusSubmitted},
+		{ArticleActionSubmit, model.ArticleStatusPublished, false, 0},
+		{ArticleActionWithdraw, model.ArticleStatusSubmitted, true, model.ArticleStatusDraft},
+		{ArticleActionWithdraw, model.ArticleStatusApproved, true, model.ArticleStatusDraft},
+		{ArticleActionWithdraw, model.ArticleStatusScheduled, false, 0},
+		{ArticleActionApprove, model.ArticleStatusSubmitted, true, model.ArticleStatusApproved},
+		{ArticleActionApprove, model.ArticleStatusDraft, false, 0},
+		{ArticleActionRequestChanges, model.ArticleStatusSubmitted, true, model.ArticleStatusChangesRequested},
+		{ArticleActionRequestChanges, model.ArticleStatusApproved, true, model.ArticleStatusChangesRequested},
+		{ArticleActionRequestChanges, model.ArticleStatusPublished, false, 0},
+		{ArticleActionPublish, model.ArticleStatusApproved, true, model.ArticleStatusPublished},
+		{ArticleActionPublish, model.ArticleStatusSubmitted, false, 0},
+		{ArticleActionPublish, model.ArticleStatusDraft, false, 0},
+		{ArticleActionArchive, model.ArticleStatusPublished, true, model.ArticleStatusArchived},
+		{ArticleActionArchive, model.ArticleStatusScheduled, true, model.ArticleStatusArchived},
+		{ArticleActionArchive, model.ArticleStatusArchived, false, 0},
+		{ArticleActionReopen, model.ArticleStatusArchived, true, model.ArticleStatusDraft},
+		{ArticleActionReopen, model.ArticleStatusPublished, false, 0},
+	}
+	for _, tt := range tests {
+		rule, ok := articleTransitions[tt.action]
+		if !ok {
+			t.Fatalf("缺少操作 %s 的流转规则", tt.action)
+		}
+		if got := statusIn(tt.from, rule.from); got != tt.allowed {
+			t.Errorf("%s 在 %s 状态下 allowed = %v, want %v",
+				tt.action, model.ArticleStatusName(tt.from), got, tt.allowed)
+			continue
+		}
+		if tt.allowed && rule.to != tt.to {
+			t.Errorf("%s 之后的状态 = %s, want %s",
+				tt.action, model.ArticleStatusName(rule.to), model.ArticleStatusName(tt.to))
+		}
+	}
+}
+
+func TestArticleActionOrderCoversTransitions(t *testing.T) {
+	if len(articleActionOrder) != len(articleTransitions) {
+		t.Fatalf("articleActionOrder 有 %d 个操作，articleTransitions 有 %d 个",
+			len(articleActionOrder), len(articleTransitions))
+	}
+	for _, action := range articleActionOrder {
+		if _, ok := articleTransitions[action]; !ok {
+			t.Errorf("操作 %s 没有流转规则", action)
+		}
+	}
+}
+
+func TestInvalidTransition(t *testing.T) {
+	err := invalidTransition(ArticleActionPublish, model.ArticleStatusDraft, articleTransitions[ArticleActionPublish].from)
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("invalidTransition() = %v, want ErrInvalidTransition", err)
+	}
+}
+
+func TestCheckArticleEditable(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		role    string
+		wantErr bool
+	}{
+		{"作者修改草稿", model.ArticleStatusDraft, model.RoleAuthor, false},
+		{"作者修改被要求修改的文章", model.ArticleStatusChangesRequested, model.RoleAuthor, false},
+		{"作者修改已归档的文章", model.ArticleStatusArchived, model.RoleAuthor, false},
+		{"作者修改审核中的文章", model.ArticleStatusSubmitted, model.RoleAuthor, true},
+		{"编辑修改审核中的文章", model.ArticleStatusSubmitted, model.RoleEditor, true},
+		{"作者修改审核通过的文章", model.ArticleStatusApproved, model.RoleAuthor, true},
+		{"作者修改定时发布的文章", model.ArticleStatusScheduled, model.RoleAuthor, true},
+		{"管理员修改定时发布的文章", model.ArticleStatusScheduled, model.RoleAdmin, true},
+		{"作者修改已发布的文章", model.ArticleStatusPublished, model.RoleAuthor, true},
+		{"编辑修改已发布的文章", model.ArticleStatusPublished, model.RoleEditor, false},
+		{"管理员修改已发布的文章", model.ArticleStatusPublished, model.RoleAdmin, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkArticleEditable(&model.Article{Status: tt.status}, tt.role)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkArticleEditable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTransition) {
+				t.Fatalf("checkArticleEditable() = %v, want ErrInvalidTransition", err)
+			}
+		})
+	}
+}
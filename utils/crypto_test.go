@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func TestEncryptDecryptString(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	otherKey := bytes.Repeat([]byte{2}, 32)
+
+	sealed, err := EncryptString("JBSWY3DPEHPK3PXP", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := EncryptString("JBSWY3DPEHPK3PXP", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sealed == again {
+		t.Fatal("相同明文的密文不应相同（nonce 应随机）")
+	}
+
+	raw, _ := base64.StdEncoding.DecodeString(sealed)
+	raw[len(raw)-1] ^= 0xff
+	tampered := base64.StdEncoding.EncodeToString(raw)
+
+	tests := []struct {
+		name       string
+		ciphertext string
+		key        []byte
+		want       string
+		wantErr    bool
+	}{
+		{name: "正确的密钥", ciphertext: sealed, key: key, want: "JBSWY3DPEHPK3PXP"},
+		{name: "错误的密钥", ciphertext: sealed, key: otherKey, wantErr: true},
+		{name: "密文被篡改", ciphertext: tampered, key: key, wantErr: true},
+		{name: "密文过短", ciphertext: base64.StdEncoding.EncodeToString([]byte("short")), key: key, wantErr: true},
+		{name: "非Base64", ciphertext: "%%%", key: key, wantErr: true},
+		{name: "密钥长度无效", ciphertext: sealed, key: []byte("short"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecryptString(tt.ciphertext, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecryptString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("DecryptString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// 差异操作类型
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffSegment 一段差异：连续的相同、新增或删除内容
+type DiffSegment struct {
+	Type string `json:"type"` // equal / insert / delete
+	Text string `json:"text"`
+}
+
+// diffOp 单个记号的差异操作
+type diffOp struct {
+	kind string
+	text string
+}
+
+// diffTokens 使用 Myers 算法计算两个记号序列之间的最短编辑脚本
+func diffTokens(a, b []string) []diffOp {
+	// 去掉相同的前缀和后缀，减少计算量
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, token := range a[:prefix] {
+		ops = append(ops, diffOp{DiffEqual, token})
+	}
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, token := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{DiffEqual, token})
+	}
+	return ops
+}
+
+// maxDiffEdits 差异算法的最大编辑距离，超过时不再计算最短编辑脚本，直接视为整体替换，避免大文本占用过多内存
+const maxDiffEdits = 2000
+
+// myers Myers 差异算法（记录每一步的前沿，最后回溯得到编辑脚本）
+func myers(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	offset := max + 1
+	v := make([]int, 2*max+2)
+	// trace[d] 保存第 d 步开始前 k ∈ [-d, d] 范围内的前沿
+	var trace [][]int
+
+	for d := 0; d <= max && d <= maxDiffEdits; d++ {
+		snapshot := make([]int, 2*d+1)
+		copy(snapshot, v[offset-d:offset+d+1])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackMyers(a, b, trace, d)
+			}
+		}
+	}
+
+	// 差异过大，按整体删除再插入处理
+	ops := make([]diffOp, 0, n+m)
+	for _, token := range a {
+		ops = append(ops, diffOp{DiffDelete, token})
+	}
+	for _, token := range b {
+		ops = append(ops, diffOp{DiffInsert, token})
+	}
+	return ops
+}
+
+// backtrackMyers 根据记录的前沿回溯出编辑脚本
+func backtrackMyers(a, b []string, trace [][]int, depth int) []diffOp {
+	x, y := len(a), len(b)
+	var reversed []diffOp
+
+	for d := depth; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[d+k-1] < v[d+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[d+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, diffOp{DiffEqual, a[x]})
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, diffOp{DiffInsert, b[y]})
+		} else {
+			x--
+			reversed = append(reversed, diffOp{DiffDelete, a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, diffOp{DiffEqual, a[x]})
+	}
+
+	ops := make([]diffOp, len(reversed))
+	for i, op := range reversed {
+		ops[len(reversed)-1-i] = op
+	}
+	return ops
+}
+
+// splitLines 按行拆分文本，每行不含换行符
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// UnifiedDiff 生成统一格式（unified diff）的行级差异，context 为每处修改前后保留的上下文行数
+func UnifiedDiff(fromName, toName, from, to string, context int) string {
+	ops := diffTokens(splitLines(from), splitLines(to))
+
+	// 找出所有修改的位置，相邻修改的上下文重叠时合并为一个块
+	type hunk struct{ start, end int }
+	var hunks []hunk
+	for i, op := range ops {
+		if op.kind == DiffEqual {
+			continue
+		}
+		start, end := i-context, i+context+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(ops) {
+			end = len(ops)
+		}
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1].end {
+			hunks[len(hunks)-1].end = end
+		} else {
+			hunks = append(hunks, hunk{start, end})
+		}
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	// 记录每个操作之前两侧已经过的行数，用于计算块头的行号
+	fromLine, toLine := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for i, op := range ops {
+		fromLine[i+1], toLine[i+1] = fromLine[i], toLine[i]
+		if op.kind != DiffInsert {
+			fromLine[i+1]++
+		}
+		if op.kind != DiffDelete {
+			toLine[i+1]++
+		}
+	}
+
+	for _, h := range hunks {
+		fromCount := fromLine[h.end] - fromLine[h.start]
+		toCount := toLine[h.end] - toLine[h.start]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(fromLine[h.start], fromCount), hunkRange(toLine[h.start], toCount))
+		for _, op := range ops[h.start:h.end] {
+			switch op.kind {
+			case DiffEqual:
+				sb.WriteString(" ")
+			case DiffInsert:
+				sb.WriteString("+")
+			case DiffDelete:
+				sb.WriteString("-")
+			}
+			sb.WriteString(op.text)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// hunkRange 生成块头中的行范围，格式与 GNU diff 一致
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// WordDiff 生成词级差异：英文等按单词和空白拆分，汉字、假名等按单个字符拆分，标点单独成词
+func WordDiff(from, to string) []DiffSegment {
+	ops := diffTokens(splitWords(from), splitWords(to))
+
+	// 合并相同类型的连续记号
+	var segments []DiffSegment
+	for _, op := range ops {
+		if n := len(segments); n > 0 && segments[n-1].Type == op.kind {
+			segments[n-1].Text += op.text
+			continue
+		}
+		segments = append(segments, DiffSegment{Type: op.kind, Text: op.text})
+	}
+	return segments
+}
+
+// splitWords 将文本拆分为词级记号，拼接所有记号可还原原文
+func splitWords(text string) []string {
+	var tokens []string
+	var current strings.Builder
+	currentClass := 0
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range text {
+		class := wordClass(r)
+		// 汉字等表意文字和标点每个字符单独成词
+		if class == wordClassSingle {
+			flush()
+			tokens = append(tokens, string(r))
+			currentClass = 0
+			continue
+		}
+		if class != currentClass {
+			flush()
+			currentClass = class
+		}
+		current.WriteRune(r)
+	}
+	flush()
+	return tokens
+}
+
+// 词级拆分的字符类别
+const (
+	wordClassWord = iota + 1
+	wordClassSpace
+	wordClassSingle
+)
+
+// wordClass 返回字符所属的类别，同类字符（单词字符、空白）连续出现时合并为一个记号
+func wordClass(r rune) int {
+	switch {
+	case unicode.IsSpace(r):
+		return wordClassSpace
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+		return wordClassSingle
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+		return wordClassWord
+	default:
+		return wordClassSingle
+	}
+}
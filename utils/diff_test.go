@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// applyDiffOps 根据编辑脚本还原两侧的记号序列
+func applyDiffOps(ops []diffOp) (from, to []string) {
+	for _, op := range ops {
+		if op.kind != DiffInsert {
+			from = append(from, op.text)
+		}
+		if op.kind != DiffDelete {
+			to = append(to, op.text)
+		}
+	}
+	return from, to
+}
+
+func TestDiffTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		edits int // 最短编辑脚本中的新增和删除数
+	}{
+		{"两侧都为空", "", "", 0},
+		{"完全相同", "abc", "abc", 0},
+		{"全部新增", "", "abc", 3},
+		{"全部删除", "abc", "", 3},
+		{"中间替换", "abcd", "axcd", 2},
+		{"前后都有修改", "abcabba", "cbabac", 5},
+		{"相同前缀和后缀", "xxabyy", "xxbayy", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := strings.Split(tt.a, ""), strings.Split(tt.b, "")
+			ops := diffTokens(a, b)
+
+			from, to := applyDiffOps(ops)
+			if strings.Join(from, "") != tt.a || strings.Join(to, "") != tt.b {
+				t.Fatalf("编辑脚本还原为 %q -> %q, want %q -> %q", strings.Join(from, ""), strings.Join(to, ""), tt.a, tt.b)
+			}
+			edits := 0
+			for _, op := range ops {
+				if op.kind != DiffEqual {
+					edits++
+				}
+			}
+			if edits != tt.edits {
+				t.Errorf("编辑数 = %d, want %d", edits, tt.edits)
+			}
+		})
+	}
+}
+
+func TestDiffTokensTooManyEdits(t *testing.T) {
+	a := make([]string, maxDiffEdits)
+	b := make([]string, maxDiffEdits)
+	for i := range a {
+		a[i], b[i] = "a", "b"
+	}
+
+	ops := diffTokens(a, b)
+	from, to := applyDiffOps(ops)
+	if !reflect.DeepEqual(from, a) || !reflect.DeepEqual(to, b) {
+		t.Fatal("差异过大时编辑脚本应能还原两侧的内容")
+	}
+	for i, op := range ops {
+		want := DiffDelete
+		if i >= len(a) {
+			want = DiffInsert
+		}
+		if op.kind != want {
+			t.Fatalf("ops[%d].kind = %s, want %s", i, op.kind, want)
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		context  int
+		want     string
+	}{
+		{
+			name: "内容相同",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		{
+			name:    "修改一行",
+			from:    "a\nb\nc\n",
+			to:      "a\nB\nc\n",
+			context: 1,
+			want:    "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:    "在空文本中新增",
+			from:    "",
+			to:      "a\nb\n",
+			context: 3,
+			want:    "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:    "删除唯一的一行",
+			from:    "a\n",
+			to:      "",
+			context: 3,
+			want:    "--- old\n+++ new\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name:    "相距较远的修改分为两个块",
+			from:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+			to:      "1\nX\n3\n4\n5\n6\nY\n8\n",
+			context: 1,
+			want: "--- old\n+++ new\n" +
+				"@@ -1,3 +1,3 @@\n 1\n-2\n+X\n 3\n" +
+				"@@ -6,3 +6,3 @@\n 6\n-7\n+Y\n 8\n",
+		},
+		{
+			name:    "上下文重叠的修改合并为一个块",
+			from:    "1\n2\n3\n4\n5\n",
+			to:      "1\nX\n3\nY\n5\n",
+			context: 1,
+			want:    "--- old\n+++ new\n@@ -1,5 +1,5 @@\n 1\n-2\n+X\n 3\n-4\n+Y\n 5\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("old", "new", tt.from, tt.to, tt.context); got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"hello world", []string{"hello", " ", "world"}},
+		{"foo_bar42  baz", []string{"foo_bar42", "  ", "baz"}},
+		{"你好，世界", []string{"你", "好", "，", "世", "界"}},
+		{"Go语言v1.25", []string{"Go", "语", "言", "v1", ".", "25"}},
+	}
+	for _, tt := range tests {
+		got := splitWords(tt.text)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitWords(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+		if strings.Join(got, "") != tt.text {
+			t.Errorf("splitWords(%q) 拼接后无法还原原文", tt.text)
+		}
+	}
+}
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []DiffSegment
+	}{
+		{
+			name: "替换单词",
+			from: "the quick fox",
+			to:   "the slow fox",
+			want: []DiffSegment{
+				{DiffEqual, "the "},
+				{DiffDelete, "quick"},
+				{DiffInsert, "slow"},
+				{DiffEqual, " fox"},
+			},
+		},
+		{
+			name: "中文按字比较",
+			from: "今天天气很好",
+			to:   "今天天气不好",
+			want: []DiffSegment{
+				{DiffEqual, "今天天气"},
+				{DiffDelete, "很"},
+				{DiffInsert, "不"},
+				{DiffEqual, "好"},
+			},
+		},
+		{
+			name: "只有新增",
+			from: "",
+			to:   "new",
+			want: []DiffSegment{{DiffInsert, "new"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WordDiff(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WordDiff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
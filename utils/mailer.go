@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidMailHeader 收件人或主题包含换行符，可能导致邮件头注入
+var ErrInvalidMailHeader = errors.New("收件人或主题包含非法字符")
+
+// checkMailHeaders 拒绝包含 CR/LF 的收件人和主题
+func checkMailHeaders(to, subject string) error {
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return ErrInvalidMailHeader
+	}
+	return nil
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// SMTPMailer 通过SMTP服务器发送邮件
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewSMTPMailer 创建SMTP邮件发送器
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+// Send 发送纯文本邮件，主题按 RFC 2047 编码以支持中文
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	if err := checkMailHeaders(to, subject); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + m.From,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("UTF-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	return smtp.SendMail(m.Host+":"+m.Port, auth, m.From, []string{to}, []byte(msg))
+}
+
+// FileMailer 将邮件写入文件或标准输出，用于本地开发和测试
+type FileMailer struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewFileMailer 创建文件邮件发送器，path 为空时输出到标准输出
+func NewFileMailer(path string) *FileMailer {
+	return &FileMailer{path: path}
+}
+
+// Send 将邮件内容追加写入文件
+func (m *FileMailer) Send(to, subject, body string) error {
+	if err := checkMailHeaders(to, subject); err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var w io.Writer = os.Stdout
+	if m.path != "" {
+		file, err := os.OpenFile(m.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	_, err := fmt.Fprintf(w, "======== %s ========\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(CustomTimeFormat), to, subject, body)
+	return err
+}
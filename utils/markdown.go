@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// RenderVersion 渲染规则的版本，修改 Markdown 扩展或净化规则后需要递增，
+// 已保存的渲染结果会在服务启动时按新规则重新生成
+const RenderVersion = 1
+
+// markdown Markdown 渲染器：GFM（表格、删除线、自动链接、任务列表）、脚注，
+// 代码块使用 chroma 按语言高亮（输出 CSS 类名，样式由前端引入）。
+// 允许内嵌原始 HTML，输出统一经过 htmlPolicy 净化
+var markdown = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		extension.Footnote,
+		highlighting.NewHighlighting(
+			highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+		),
+	),
+	goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+)
+
+// htmlPolicy HTML 净化白名单：在 UGC 策略的基础上允许代码高亮、脚注和任务列表需要的属性
+var htmlPolicy = newHTMLPolicy()
+
+// newHTMLPolicy 创建 HTML 净化策略
+func newHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^[A-Za-z0-9_ -]+$`)).
+		OnElements("pre", "code", "span", "div", "a", "sup", "li", "ul", "ol")
+	policy.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-[a-z]+$`)).OnElements("a", "div")
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	return policy
+}
+
+// RenderMarkdown 将 Markdown 渲染为净化后的 HTML
+func RenderMarkdown(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return htmlPolicy.Sanitize(buf.String()), nil
+}
+
+// SanitizeHTML 按白名单净化 HTML，移除脚本、事件属性和危险链接等
+func SanitizeHTML(source string) string {
+	return htmlPolicy.Sanitize(source)
+}
+
+// RenderPlainText 将纯文本转换为 HTML：转义特殊字符，空行分段，段内换行转为 <br>
+func RenderPlainText(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+
+	var sb strings.Builder
+	for _, paragraph := range strings.Split(source, "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+		sb.WriteString("<p>")
+		sb.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>\n"))
+		sb.WriteString("</p>\n")
+	}
+	return sb.String()
+}
+
+// textPolicy 去除全部标签的净化策略，用于从 HTML 中提取纯文本
+var textPolicy = bluemonday.StrictPolicy()
+
+// blockBoundary 块级元素的结束标签和换行标签
+var blockBoundary = regexp.MustCompile(`(?i)</(p|div|li|h[1-6]|td|th|tr|pre|blockquote|table|ul|ol|dd|dt)>|<br\s*/?>`)
+
+// HTMLToText 提取 HTML 中的纯文本：去除标签、还原实体，连续空白合并为一个空格
+func HTMLToText(source string) string {
+	// 块级元素之后补上空格，避免相邻段落的文字粘连
+	text := textPolicy.Sanitize(blockBoundary.ReplaceAllString(source, "$0 "))
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength slug 的最大长度（字符数，不含冲突后缀）
+const MaxSlugLength = 80
+
+// pinyinArgs 汉字转拼音参数：不带声调，多音字取常用读音
+var pinyinArgs = pinyin.NewArgs()
+
+// Slugify 将标题转换为URL友好的 slug：
+// 汉字转换为拼音，拉丁、希腊、西里尔字母去掉变音符号，其他文字（假名、谚文等）原样保留，
+// 字母统一小写，其余字符作为单词分隔符，单词之间用 "-" 连接。无法生成时返回空字符串
+func Slugify(text string) string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range norm.NFKC.String(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			// 每个汉字单独成词
+			flush()
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) > 0 {
+				words = append(words, py[0])
+			}
+		case unicode.In(r, unicode.Latin, unicode.Greek, unicode.Cyrillic):
+			for _, d := range norm.NFD.String(string(r)) {
+				if unicode.IsLetter(d) {
+					word.WriteRune(unicode.ToLower(d))
+				}
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	// 超出长度时按单词截断
+	slug := ""
+	for _, w := range words {
+		candidate := w
+		if slug != "" {
+			candidate = slug + "-" + w
+		}
+		if len([]rune(candidate)) > MaxSlugLength {
+			if slug == "" {
+				slug = string([]rune(w)[:MaxSlugLength])
+			}
+			break
+		}
+		slug = candidate
+	}
+	return slug
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"英文标题", "Hello, World!", "hello-world"},
+		{"汉字转拼音", "你好世界", "ni-hao-shi-jie"},
+		{"中英混排", "Go 1.25 发布", "go-1-25-fa-bu"},
+		{"去掉变音符号", "Café Déjà Vu", "cafe-deja-vu"},
+		{"全角字符", "Ｆｕｌｌｗｉｄｔｈ ＡＢＣ", "fullwidth-abc"},
+		{"西里尔字母", "Привет мир", "привет-мир"},
+		{"希腊字母", "Αθήνα", "αθηνα"},
+		{"假名原样保留", "こんにちは 世界", "こんにちは-shi-jie"},
+		{"多余的分隔符", "  --a--b--  ", "a-b"},
+		{"只有标点", "!!!", ""},
+		{"空字符串", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.text); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugifyTruncates(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"按单词截断", strings.Repeat("abc ", 30), strings.TrimSuffix(strings.Repeat("abc-", 20), "-")},
+		{"单个超长单词直接截断", strings.Repeat("a", 100), strings.Repeat("a", MaxSlugLength)},
+		{"按字符而不是字节计算长度", strings.Repeat("é", 100), strings.Repeat("e", MaxSlugLength)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Slugify(tt.text)
+			if got != tt.want {
+				t.Errorf("Slugify() = %q, want %q", got, tt.want)
+			}
+			if n := len([]rune(got)); n > MaxSlugLength {
+				t.Errorf("slug 长度 = %d, 超过 %d", n, MaxSlugLength)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret RFC 6238 附录B测试向量使用的 SHA1 密钥 "12345678901234567890"
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	key, err := totpEncoding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// RFC 6238 给出8位验证码，这里取后6位
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/TOTPPeriod); got != tt.want {
+			t.Errorf("totpCode(T=%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	key, _ := totpEncoding.DecodeString(rfc6238Secret)
+	step := now.Unix() / TOTPPeriod
+	codeAt := func(offset int64) string { return totpCode(key, step+offset) }
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "当前时间步", secret: rfc6238Secret, code: codeAt(0), wantStep: step, wantOK: true},
+		{name: "前一个时间步", secret: rfc6238Secret, code: codeAt(-1), wantStep: step - 1, wantOK: true},
+		{name: "后一个时间步", secret: rfc6238Secret, code: codeAt(1), wantStep: step + 1, wantOK: true},
+		{name: "超出允许的偏差", secret: rfc6238Secret, code: codeAt(2)},
+		{name: "首尾空白", secret: rfc6238Secret, code: " " + codeAt(0) + " ", wantStep: step, wantOK: true},
+		{name: "小写密钥", secret: strings.ToLower(rfc6238Secret), code: codeAt(0), wantStep: step, wantOK: true},
+		{name: "位数不对", secret: rfc6238Secret, code: codeAt(0)[:5]},
+		{name: "错误的验证码", secret: rfc6238Secret, code: "000000"},
+		{name: "无效的密钥", secret: "not base32!", code: codeAt(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := ValidateTOTP(tt.secret, tt.code, now)
+			if ok != tt.wantOK || gotStep != tt.wantStep {
+				t.Fatalf("ValidateTOTP() = (%d, %v), want (%d, %v)", gotStep, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("密钥 %q 不是20字节的Base32编码: %v", secret, err)
+	}
+
+	code := totpCode(key, time.Now().Unix()/TOTPPeriod)
+	if _, ok := ValidateTOTP(secret, code, time.Now()); !ok {
+		t.Fatal("生成的密钥无法校验自身的验证码")
+	}
+}